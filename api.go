@@ -0,0 +1,77 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+)
+
+// apiKeyHeader carries the key for the read-only queue API.
+const apiKeyHeader = "X-API-Key"
+
+// authorizeAPI checks the request's API key, writing 401 if it is missing or
+// wrong.
+func (sh *SlackHandler) authorizeAPI(w http.ResponseWriter, r *http.Request) bool {
+	key := r.Header.Get(apiKeyHeader)
+	if key == "" || subtle.ConstantTimeCompare([]byte(key), []byte(sh.APIKey)) != 1 {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid or missing " + apiKeyHeader})
+		return false
+	}
+	return true
+}
+
+// HandleAPIQueues serves GET /api/queues, optionally filtered with
+// ?channel=C123.
+func (sh *SlackHandler) HandleAPIQueues(w http.ResponseWriter, r *http.Request) {
+	if !sh.authorizeAPI(w, r) {
+		return
+	}
+
+	sh.mu.Lock()
+	queues, err := sh.Store.List()
+	sh.mu.Unlock()
+	if err != nil {
+		log.Printf("[ERROR] Failed to list queues for API: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "queue store unavailable"})
+		return
+	}
+
+	if channel := r.URL.Query().Get("channel"); channel != "" {
+		queues = inChannel(queues, channel)
+	}
+	if queues == nil {
+		queues = []*Queue{}
+	}
+	writeJSON(w, http.StatusOK, queues)
+}
+
+// HandleAPIQueue serves GET /api/queues/{id}, where id is the queue ID in
+// the configured format.
+func (sh *SlackHandler) HandleAPIQueue(w http.ResponseWriter, r *http.Request) {
+	if !sh.authorizeAPI(w, r) {
+		return
+	}
+
+	sh.mu.Lock()
+	queue, err := sh.anyTeam().findQueue(r.PathValue("id"))
+	sh.mu.Unlock()
+	switch {
+	case errors.Is(err, ErrQueueNotFound):
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "queue not found"})
+	case err != nil:
+		log.Printf("[ERROR] Failed to look up queue for API: %v", err)
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "queue store unavailable"})
+	default:
+		writeJSON(w, http.StatusOK, queue)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Printf("[WARN] Failed to write API response: %v", err)
+	}
+}
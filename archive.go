@@ -0,0 +1,122 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// isArchived reports whether the queue has been archived. Archived queues
+// are kept as a record but left out of the default list and the App Home.
+func (q *Queue) isArchived() bool {
+	return !q.ArchivedAt.IsZero()
+}
+
+// withArchived keeps the queues whose archived state matches archived.
+func withArchived(queues []*Queue, archived bool) []*Queue {
+	var kept []*Queue
+	for _, queue := range queues {
+		if queue.isArchived() == archived {
+			kept = append(kept, queue)
+		}
+	}
+	return kept
+}
+
+// handleQueueArchive files away a finished queue, unlike remove, which
+// deletes it.
+func (sh *SlackHandler) handleQueueArchive(w http.ResponseWriter, ev *slackevents.MessageEvent) {
+	ref, err := sh.parseQueueID(ev.Text)
+	if err != nil {
+		sh.replyError(ev, err.Error())
+		return
+	}
+
+	sh.mu.Lock()
+	queue, err := sh.findQueue(ref)
+	if err == nil {
+		queue, err = sh.Store.Update(queue.ID, func(q *Queue) error {
+			switch {
+			case q.Owner != ev.User && !sh.isAdmin(ev.User):
+				return rejection(t("archive.owner_only", q.Owner))
+			case q.isArchived():
+				return rejection(t("archive.already", q.DisplayID()))
+			case q.isActive():
+				return rejection(t("archive.active", q.DisplayID(), q.status().label()))
+			}
+			q.ArchivedAt = time.Now()
+			q.UpdatedAt = q.ArchivedAt
+			return nil
+		})
+	}
+	if err == nil {
+		sh.recordEvent(EventQueueArchived, queue, ev.User)
+	}
+	sh.mu.Unlock()
+
+	if err != nil {
+		sh.replyQueueError(ev, err)
+		return
+	}
+	msg := t("archive.done", queue.DisplayID())
+	sh.API.PostMessage(ev.Channel, slack.MsgOptionText(msg, false))
+}
+
+// autoArchiveInterval is how often approved queues are checked for archiving.
+const autoArchiveInterval = time.Minute
+
+// approvedAt returns when the queue was fully approved, using the last update
+// for queues approved before ApprovedAt was tracked.
+func (q *Queue) approvedAt() time.Time {
+	if !q.ApprovedAt.IsZero() {
+		return q.ApprovedAt
+	}
+	return q.UpdatedAt
+}
+
+// StartAutoArchive launches a background loop that archives queues once they
+// have been approved for longer than after, so they stay visible for a while
+// before leaving the list. A non-positive after disables it.
+func (sh *SlackHandler) StartAutoArchive(after time.Duration) {
+	if after <= 0 {
+		return
+	}
+
+	log.Printf("[INFO] Archiving approved queues after %s", after)
+	go func() {
+		ticker := time.NewTicker(min(after, autoArchiveInterval))
+		defer ticker.Stop()
+		for now := range ticker.C {
+			sh.archiveApproved(now, after)
+		}
+	}()
+}
+
+// archiveApproved archives the approved queues whose grace period has passed.
+func (sh *SlackHandler) archiveApproved(now time.Time, after time.Duration) {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	queues, err := sh.Store.List()
+	if err != nil {
+		log.Printf("[ERROR] Failed to list queues for auto-archive: %v", err)
+		return
+	}
+	for _, queue := range queues {
+		if queue.status() != StatusApproved || queue.isArchived() || now.Sub(queue.approvedAt()) < after {
+			continue
+		}
+		archived, err := sh.Store.Update(queue.ID, func(q *Queue) error {
+			q.ArchivedAt = now
+			return nil
+		})
+		if err != nil {
+			log.Printf("[ERROR] Failed to auto-archive queue %s: %v", queue.DisplayID(), err)
+			continue
+		}
+		sh.recordEvent(EventQueueArchived, archived, "")
+	}
+}
@@ -0,0 +1,410 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// parseAssignArgs validates "queue assign|unassign <id> @user" and returns the
+// queue reference and the canonical mention. It calls the Slack API, so call
+// it before taking sh.mu.
+func (sh *SlackHandler) parseAssignArgs(command string) (string, string, error) {
+	parts := strings.Fields(command)
+	if len(parts) != 4 {
+		// Handlers get the canonical command, but don't rely on it here
+		name := strings.Join(parts[:min(len(parts), 2)], " ")
+		return "", "", errors.New(t("assign.usage", name))
+	}
+	if !sh.validQueueID(parts[2]) {
+		return "", "", errors.New(t("queue.invalid_id"))
+	}
+
+	tags, _ := sh.normalizeTags(parts[3:])
+	if len(tags) != 1 || !mentionPattern.MatchString(tags[0]) {
+		return "", "", errors.New(t("user.not_found", parts[3]))
+	}
+	return parts[2], tags[0], nil
+}
+
+// hasTag reports whether tag is a pending reviewer on the queue.
+func (q *Queue) hasTag(tag string) bool {
+	for _, existing := range q.Tags {
+		if existing == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func (sh *SlackHandler) handleQueueAssign(w http.ResponseWriter, ev *slackevents.MessageEvent) {
+	ref, tag, err := sh.parseAssignArgs(ev.Text)
+	if err != nil {
+		sh.replyError(ev, err.Error())
+		return
+	}
+
+	sh.mu.Lock()
+	added := false
+	queue, err := sh.findQueue(ref)
+	if err == nil && !queue.hasTag(tag) {
+		var kept []string
+		if kept, _, err = sh.applyCapacity([]string{tag}); err == nil && len(kept) == 0 {
+			// Skipping the only reviewer leaves nothing to assign
+			err = rejection(t("capacity.user_over", tag, sh.Capacity.MaxPerReviewer))
+		}
+	}
+	if err == nil {
+		queue, err = sh.Store.Update(queue.ID, func(q *Queue) error {
+			if added = !q.hasTag(tag); !added {
+				return nil
+			}
+			if err := q.checkActive(); err != nil {
+				return err
+			}
+			q.Tags = append(q.Tags, tag)
+			q.UpdatedAt = time.Now()
+			q.resetReminders()
+			return nil
+		})
+	}
+	if err == nil && added {
+		sh.refreshHomes(queue)
+		sh.refreshStatusCard(queue)
+	}
+	sh.mu.Unlock()
+
+	if err != nil {
+		sh.replyQueueError(ev, err)
+		return
+	}
+	if !added {
+		sh.replyError(ev, t("assign.already", tag, queue.DisplayID()))
+		return
+	}
+
+	msg := t("assign.added", tag, queue.DisplayID())
+	sh.API.PostMessage(ev.Channel, slack.MsgOptionText(msg, false))
+	sh.notifyAssigned(queue, tag, ev.User)
+}
+
+// notifyAssigned lets a newly tagged reviewer know directly.
+func (sh *SlackHandler) notifyAssigned(queue *Queue, tag, actor string) {
+	userID, _ := parseMention(tag)
+	notice := t("assign.notice", actor, queue.Title, queue.MRLink)
+	if !queue.SLADeadline.IsZero() {
+		notice += t("queue.due_line", formatTimestamp(queue.SLADeadline, sh.Users.Location(userID)))
+	}
+	sh.notifyUser(userID, notice)
+}
+
+func (sh *SlackHandler) handleQueueUnassign(w http.ResponseWriter, ev *slackevents.MessageEvent) {
+	ref, tag, err := sh.parseAssignArgs(ev.Text)
+	if err != nil {
+		sh.replyError(ev, err.Error())
+		return
+	}
+
+	sh.mu.Lock()
+	queue, err := sh.findQueue(ref)
+	if err == nil {
+		queue, err = sh.Store.Update(queue.ID, func(q *Queue) error {
+			if err := q.checkActive(); err != nil {
+				return err
+			}
+			for i, existing := range q.Tags {
+				if existing == tag {
+					if len(q.Tags) == 1 {
+						return rejection(t("tags.need_one"))
+					}
+					q.Tags = append(q.Tags[:i], q.Tags[i+1:]...)
+					q.UpdatedAt = time.Now()
+					return nil
+				}
+			}
+			return rejection(t("unassign.not_pending", tag))
+		})
+	}
+	if err == nil {
+		sh.refreshHomes(queue)
+		sh.refreshUserHomes(queue.TeamID, tagUsers([]string{tag})...)
+		sh.refreshStatusCard(queue)
+	}
+	sh.mu.Unlock()
+
+	if err != nil {
+		sh.replyQueueError(ev, err)
+		return
+	}
+	msg := t("unassign.removed", tag, queue.DisplayID())
+	sh.API.PostMessage(ev.Channel, slack.MsgOptionText(msg, false))
+}
+
+func (sh *SlackHandler) handleQueueTags(w http.ResponseWriter, ev *slackevents.MessageEvent) {
+	parts := strings.Fields(ev.Text)
+	if len(parts) < 5 || (parts[3] != "set" && parts[3] != "add" && parts[3] != "remove") {
+		sh.replyError(ev, t("tags.usage"))
+		return
+	}
+	if !sh.validQueueID(parts[2]) {
+		sh.replyError(ev, t("queue.invalid_id"))
+		return
+	}
+	op := parts[3]
+
+	tags, unresolved := sh.normalizeTags(parts[4:])
+	for _, tag := range tags {
+		if !mentionPattern.MatchString(tag) {
+			unresolved = append(unresolved, tag)
+		}
+	}
+	if len(unresolved) > 0 {
+		sh.replyError(ev, t("user.not_found", strings.Join(unresolved, ", ")))
+		return
+	}
+
+	sh.mu.Lock()
+	queue, err := sh.findQueue(parts[2])
+	if err == nil && queue.Owner != ev.User && !sh.isAdmin(ev.User) {
+		err = rejection(t("tags.owner_only", queue.Owner))
+	}
+	if err == nil && op != "remove" {
+		var added []string
+		for _, tag := range tags {
+			if !queue.hasTag(tag) {
+				added = append(added, tag)
+			}
+		}
+		var kept []string
+		if kept, _, err = sh.applyCapacity(added); err == nil && len(kept) < len(added) {
+			err = rejection(t("capacity.each", sh.Capacity.MaxPerReviewer))
+		}
+	}
+	var removed []string
+	if err == nil {
+		queue, err = sh.Store.Update(queue.ID, func(q *Queue) error {
+			if err := q.checkActive(); err != nil {
+				return err
+			}
+			previous := q.Tags
+			switch op {
+			case "set":
+				if q.RequiredApprovals > 0 {
+					// Anyone may approve, but reviewers taken off the queue
+					// no longer count
+					q.Approvers = tagsNotIn(q.Approvers, tagsNotIn(q.Tags, tags))
+					q.Tags = tags
+					break
+				}
+				// Approved tags have left Tags: keep the approvals of those
+				// still listed rather than making them pending again
+				q.Approvers = tagsNotIn(q.Approvers, tagsNotIn(q.Approvers, tags))
+				if q.Tags = tagsNotIn(tags, q.Approvers); len(q.Tags) == 0 {
+					return rejection(t("tags.all_approved"))
+				}
+			case "add":
+				for _, tag := range tags {
+					if !q.hasTag(tag) {
+						q.Tags = append(q.Tags, tag)
+					}
+				}
+			case "remove":
+				remove := make(map[string]bool, len(tags))
+				for _, tag := range tags {
+					remove[tag] = true
+				}
+				var remaining []string
+				for _, tag := range q.Tags {
+					if !remove[tag] {
+						remaining = append(remaining, tag)
+					}
+				}
+				if len(remaining) == 0 {
+					return rejection(t("tags.need_one"))
+				}
+				q.Tags = remaining
+			}
+			removed = tagsNotIn(previous, q.Tags)
+			q.UpdatedAt = time.Now()
+			return nil
+		})
+	}
+	if err == nil {
+		sh.refreshHomes(queue)
+		sh.refreshUserHomes(queue.TeamID, tagUsers(removed)...)
+		sh.refreshStatusCard(queue)
+	}
+	sh.mu.Unlock()
+
+	if err != nil {
+		sh.replyQueueError(ev, err)
+		return
+	}
+	msg := t("tags.result", queue.DisplayID(), strings.Join(queue.Tags, ", "))
+	sh.API.PostMessage(ev.Channel, slack.MsgOptionText(msg, false))
+}
+
+// tagsNotIn returns the tags that are not in other, in order.
+func tagsNotIn(tags, other []string) []string {
+	exclude := make(map[string]bool, len(other))
+	for _, tag := range other {
+		exclude[tag] = true
+	}
+	var result []string
+	for _, tag := range tags {
+		if !exclude[tag] {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+// handleQueueReassign swaps every reviewer of a queue for new ones, e.g. when
+// the original reviewers are away. Approvals given so far are cleared.
+func (sh *SlackHandler) handleQueueReassign(w http.ResponseWriter, ev *slackevents.MessageEvent) {
+	parts := strings.Fields(ev.Text)
+	if len(parts) < 4 {
+		sh.replyError(ev, t("reassign.usage"))
+		return
+	}
+	if !sh.validQueueID(parts[2]) {
+		sh.replyError(ev, t("queue.invalid_id"))
+		return
+	}
+
+	tags, unresolved := sh.normalizeTags(parts[3:])
+	for _, tag := range tags {
+		if !mentionPattern.MatchString(tag) {
+			unresolved = append(unresolved, tag)
+		}
+	}
+	if len(unresolved) > 0 {
+		sh.replyError(ev, t("user.not_found", strings.Join(unresolved, ", ")))
+		return
+	}
+
+	sh.mu.Lock()
+	var removed, added []string
+	var released string
+	var pin reviewPin
+	queue, err := sh.findQueue(parts[2])
+	if err == nil && queue.Owner != ev.User && !sh.isAdmin(ev.User) {
+		err = rejection(t("reassign.owner_only", queue.Owner))
+	}
+	if err == nil {
+		fresh := tagsNotIn(tags, queue.Tags)
+		var kept []string
+		if kept, _, err = sh.applyCapacity(fresh); err == nil && len(kept) < len(fresh) {
+			err = rejection(t("capacity.each", sh.Capacity.MaxPerReviewer))
+		}
+	}
+	if err == nil {
+		queue, err = sh.Store.Update(queue.ID, func(q *Queue) error {
+			released, pin = "", reviewPin{}
+			if err := q.checkActive(); err != nil {
+				return err
+			}
+			if q.status() == StatusInReview && !slices.Contains(tags, fmt.Sprintf("<@%s>", q.Reviewer)) {
+				// The claimed reviewer was swapped out
+				if err := q.transition(StatusOpen); err != nil {
+					return err
+				}
+				released = q.Reviewer
+				q.Reviewer = ""
+				pin = takeReviewPin(q)
+			}
+			removed, added = tagsNotIn(q.Tags, tags), tagsNotIn(tags, q.Tags)
+			q.Tags = tags
+			q.Approvers = nil
+			q.UpdatedAt = time.Now()
+			q.resetReminders()
+			return nil
+		})
+	}
+	if err == nil {
+		sh.unpinReviewMessage(pin)
+		sh.recordEvent(EventQueueReassigned, queue, ev.User)
+		sh.refreshUserHomes(queue.TeamID, append(tagUsers(removed), released)...)
+		sh.refreshStatusCard(queue)
+	}
+	sh.mu.Unlock()
+
+	if err != nil {
+		sh.replyQueueError(ev, err)
+		return
+	}
+	msg := t("reassign.done", queue.DisplayID(), strings.Join(queue.Tags, ", "))
+	sh.API.PostMessage(ev.Channel, slack.MsgOptionText(msg, false))
+
+	for _, tag := range added {
+		sh.notifyAssigned(queue, tag, ev.User)
+	}
+	for _, tag := range removed {
+		userID, _ := parseMention(tag)
+		sh.notifyUser(userID, t("reassign.released", ev.User, queue.Title))
+	}
+}
+
+// handleQueueTake tags the user on a queue and claims it for review in one
+// step.
+func (sh *SlackHandler) handleQueueTake(w http.ResponseWriter, ev *slackevents.MessageEvent) {
+	ref, err := sh.parseQueueID(ev.Text)
+	if err != nil {
+		sh.replyError(ev, err.Error())
+		return
+	}
+	tag := fmt.Sprintf("<@%s>", ev.User)
+
+	sh.mu.Lock()
+	queue, err := sh.findQueue(ref)
+	if err == nil && !queue.hasTag(tag) {
+		var kept []string
+		if kept, _, err = sh.applyCapacity([]string{tag}); err == nil && len(kept) == 0 {
+			err = rejection(t("capacity.you_over", sh.Capacity.MaxPerReviewer))
+		}
+	}
+	if err == nil {
+		queue, err = sh.Store.Update(queue.ID, func(q *Queue) error {
+			switch {
+			case q.Owner == ev.User:
+				return rejection(t("take.own"))
+			case q.status() == StatusInReview && q.Reviewer == ev.User:
+				return rejection(t("review.already_yours"))
+			case q.status() == StatusInReview && q.Reviewer != "":
+				return rejection(t("review.taken", q.Reviewer))
+			}
+			if err := q.transition(StatusInReview); err != nil {
+				return err
+			}
+			if !q.hasTag(tag) {
+				q.Tags = append(q.Tags, tag)
+			}
+			q.Reviewer = ev.User
+			q.UpdatedAt = time.Now()
+			q.resetReminders()
+			return nil
+		})
+	}
+	if err != nil {
+		sh.mu.Unlock()
+		sh.replyQueueError(ev, err)
+		return
+	}
+	sh.refreshHomes(queue)
+	sh.recordEvent(EventQueueReviewed, queue, ev.User)
+	sh.refreshStatusCard(queue)
+	queues, listErr := sh.teamQueues()
+	sh.mu.Unlock()
+
+	msg := t("take.done", ev.User, queue.DisplayID())
+	if channel, ts, err := sh.API.PostMessage(ev.Channel, slack.MsgOptionText(msg, false)); err == nil {
+		sh.pinReviewMessage(queue.ID, channel, ts)
+	}
+	sh.replyQueueList(ev, queues, listErr)
+}
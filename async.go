@@ -0,0 +1,57 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"runtime/debug"
+)
+
+// ackedResponse stands in for the http.ResponseWriter of a request that has
+// already been acknowledged. Writes are discarded.
+type ackedResponse struct {
+	header http.Header
+}
+
+func (ar *ackedResponse) Header() http.Header {
+	if ar.header == nil {
+		ar.header = make(http.Header)
+	}
+	return ar.header
+}
+
+func (ar *ackedResponse) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (ar *ackedResponse) WriteHeader(int) {}
+
+// ackAndRun acknowledges the request with 200 OK straight away, so Slack sees
+// a response within its 3-second limit, and runs fn in the background. fn
+// receives a writer that discards output, and must take sh.mu itself like any
+// handler.
+func (sh *SlackHandler) ackAndRun(w http.ResponseWriter, fn func(w http.ResponseWriter)) {
+	w.WriteHeader(http.StatusOK)
+
+	go func() {
+		// net/http no longer recovers panics for us
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("[ERROR] Panic while handling Slack request: %v\n%s", r, debug.Stack())
+			}
+		}()
+		fn(&ackedResponse{})
+	}()
+}
+
+// withResponseURL returns a copy of sh that sends private replies to the
+// response URL of a slash command or interaction. Unlike ephemeral
+// messages, these reach the user even in channels the bot is not in. An
+// empty URL returns sh.
+func (sh *SlackHandler) withResponseURL(responseURL string) *SlackHandler {
+	if responseURL == "" {
+		return sh
+	}
+	th := *sh
+	th.responseURL = responseURL
+	return &th
+}
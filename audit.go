@@ -0,0 +1,72 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// maxAuditEntries bounds the in-memory audit log; the oldest entries are
+// dropped first.
+const maxAuditEntries = 10000
+
+// AuditEntry records a single queue lifecycle event.
+type AuditEntry struct {
+	Time    time.Time `json:"time"`
+	Event   string    `json:"event"`
+	QueueID int       `json:"queue_id"`
+	Actor   string    `json:"actor"`
+	Channel string    `json:"channel"`
+	TeamID  string    `json:"team_id,omitempty"`
+}
+
+// AuditLog is an append-only, in-memory log of queue lifecycle events.
+type AuditLog struct {
+	entries []AuditEntry
+	mu      sync.Mutex
+}
+
+// NewAuditLog creates a new instance of AuditLog.
+func NewAuditLog() *AuditLog {
+	return &AuditLog{}
+}
+
+// Record appends an event for the queue to the log.
+func (al *AuditLog) Record(event string, queue *Queue, actor string) {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+
+	al.entries = append(al.entries, AuditEntry{
+		Time:    time.Now(),
+		Event:   event,
+		QueueID: queue.ID,
+		Actor:   actor,
+		Channel: queue.Channel,
+		TeamID:  queue.TeamID,
+	})
+	if len(al.entries) > maxAuditEntries {
+		al.entries = append([]AuditEntry(nil), al.entries[len(al.entries)-maxAuditEntries:]...)
+	}
+}
+
+// Since returns a copy of the entries recorded at or after t.
+func (al *AuditLog) Since(t time.Time) []AuditEntry {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+
+	var entries []AuditEntry
+	for _, entry := range al.entries {
+		if !entry.Time.Before(t) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// recordEvent writes the event to the audit log, forwards it to the outgoing
+// webhook, and refreshes the App Home of everyone involved in the queue.
+// Callers must hold sh.mu.
+func (sh *SlackHandler) recordEvent(event string, queue *Queue, actor string) {
+	sh.Audit.Record(event, queue, actor)
+	sh.Webhook.Notify(event, queue, actor)
+	sh.refreshHomes(queue)
+}
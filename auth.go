@@ -0,0 +1,52 @@
+package main
+
+import (
+	"log"
+	"time"
+)
+
+// StartAuthRefresh re-runs auth.test every interval, so a bot user that
+// changed after a token rotation is picked up and a revoked token is
+// reported. A non-positive interval disables it.
+func (sh *SlackHandler) StartAuthRefresh(interval time.Duration) {
+	if interval <= 0 {
+		log.Printf("[INFO] Bot auth refresh disabled")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			sh.refreshAuth()
+		}
+	}()
+}
+
+// refreshAuth updates BotUserID from auth.test. On failure the previous ID is
+// kept.
+func (sh *SlackHandler) refreshAuth() {
+	authResp, err := sh.API.AuthTest()
+	if err != nil {
+		log.Printf("[ERROR] Bot auth refresh failed, keeping bot user %s: %v", sh.botUserID(), err)
+		return
+	}
+
+	sh.mu.Lock()
+	previous := sh.BotUserID
+	sh.BotUserID = authResp.UserID
+	sh.mu.Unlock()
+
+	if previous != authResp.UserID {
+		log.Printf("[INFO] Bot user changed from %s to %s (%s) in team %s", previous, authResp.UserID, authResp.User, authResp.Team)
+	}
+}
+
+// botUserID returns the bot's user ID, which refreshAuth may change. It is
+// empty if the bot could not be identified.
+func (sh *SlackHandler) botUserID() string {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	return sh.BotUserID
+}
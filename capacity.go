@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Over-capacity behaviours selectable via OVER_CAPACITY.
+const (
+	// OverCapacityBlock refuses to tag a reviewer who is at the limit.
+	OverCapacityBlock = "block"
+	// OverCapacitySkip leaves reviewers at the limit off the queue.
+	OverCapacitySkip = "skip"
+)
+
+// CapacityConfig limits how many open queues a reviewer can be tagged on.
+type CapacityConfig struct {
+	// MaxPerReviewer is the limit; zero disables it.
+	MaxPerReviewer int
+	// Mode is OverCapacityBlock or OverCapacitySkip.
+	Mode string
+}
+
+// isComplete reports whether the queue has all the approvals it needs.
+func (q *Queue) isComplete() bool {
+	if q.RequiredApprovals > 0 {
+		return len(q.Approvers) >= q.RequiredApprovals
+	}
+	return len(q.Tags) == 0
+}
+
+// pendingTags returns the tags that have yet to approve. Count mode leaves
+// approvers in Tags, so they are filtered out here.
+func (q *Queue) pendingTags() []string {
+	return tagsNotIn(q.Tags, q.Approvers)
+}
+
+// reviewerLoad counts each reviewer tag's pending assignments across open
+// queues. Callers must hold sh.mu.
+func (sh *SlackHandler) reviewerLoad() (map[string]int, error) {
+	queues, err := sh.teamQueues()
+	if err != nil {
+		return nil, err
+	}
+
+	load := make(map[string]int)
+	for _, queue := range queues {
+		if !queue.isActive() {
+			continue
+		}
+		approved := make(map[string]bool, len(queue.Approvers))
+		for _, approver := range queue.Approvers {
+			approved[approver] = true
+		}
+		for _, tag := range queue.Tags {
+			if !approved[tag] {
+				load[tag]++
+			}
+		}
+	}
+	return load, nil
+}
+
+// applyCapacity checks tags against the per-reviewer limit. In
+// OverCapacityBlock it rejects the lot if any tag is at the limit; in
+// OverCapacitySkip it returns the remaining tags and the skipped ones. Callers
+// must hold sh.mu.
+func (sh *SlackHandler) applyCapacity(tags []string) (kept, skipped []string, err error) {
+	if sh.Capacity.MaxPerReviewer <= 0 || len(tags) == 0 {
+		return tags, nil, nil
+	}
+
+	load, err := sh.reviewerLoad()
+	if err != nil {
+		return nil, nil, err
+	}
+	var over []string
+	for _, tag := range tags {
+		if load[tag] >= sh.Capacity.MaxPerReviewer {
+			skipped = append(skipped, tag)
+			over = append(over, t("capacity.load", tag, load[tag]))
+		} else {
+			kept = append(kept, tag)
+		}
+	}
+	if len(over) > 0 && sh.Capacity.Mode != OverCapacitySkip {
+		return nil, nil, rejection(t("capacity.over", strings.Join(over, ", "), sh.Capacity.MaxPerReviewer))
+	}
+	return kept, skipped, nil
+}
+
+// nextPoolReviewer picks the next reviewer from the pool who is below the
+// per-reviewer limit, skipping exclude. Callers must hold sh.mu.
+func (sh *SlackHandler) nextPoolReviewer(exclude string) (string, error) {
+	if sh.Capacity.MaxPerReviewer <= 0 {
+		return sh.Reviewers.Next(exclude), nil
+	}
+
+	load, err := sh.reviewerLoad()
+	if err != nil {
+		return "", err
+	}
+	for range sh.Reviewers.Members() {
+		reviewer := sh.Reviewers.Next(exclude)
+		if reviewer == "" {
+			break
+		}
+		if load[fmt.Sprintf("<@%s>", reviewer)] < sh.Capacity.MaxPerReviewer {
+			return reviewer, nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// channelConfigKeys are the settings `queue config` can change, in display
+// order.
+var channelConfigKeys = []string{"digest", "require_mention", "approve_mode", "required_approvals", "list_mode"}
+
+// ChannelSettings are the effective settings for a channel.
+type ChannelSettings struct {
+	Digest            bool
+	RequireMention    bool
+	ApproveMode       string
+	RequiredApprovals int
+	ListMode          string
+}
+
+// ChannelConfig holds a channel's overrides. Unset fields fall back to the
+// defaults from the environment.
+type ChannelConfig struct {
+	Digest            *bool  `json:"digest,omitempty"`
+	RequireMention    *bool  `json:"require_mention,omitempty"`
+	ApproveMode       string `json:"approve_mode,omitempty"`
+	RequiredApprovals int    `json:"required_approvals,omitempty"`
+	ListMode          string `json:"list_mode,omitempty"`
+}
+
+// ChannelConfigs is the persisted per-channel configuration, keyed by channel
+// ID.
+type ChannelConfigs struct {
+	path     string
+	defaults ChannelSettings
+	configs  map[string]ChannelConfig
+	mu       sync.Mutex
+}
+
+// NewChannelConfigs creates a new instance of ChannelConfigs, loading any
+// overrides previously saved at path. An empty path keeps them in memory
+// only.
+func NewChannelConfigs(path string, defaults ChannelSettings) (*ChannelConfigs, error) {
+	cc := &ChannelConfigs{path: path, defaults: defaults, configs: make(map[string]ChannelConfig)}
+	if path == "" {
+		return cc, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cc, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read channel config: %w", err)
+	}
+	if err := json.Unmarshal(data, &cc.configs); err != nil {
+		return nil, fmt.Errorf("parse channel config: %w", err)
+	}
+	return cc, nil
+}
+
+// Settings returns the channel's overrides applied on top of the defaults.
+func (cc *ChannelConfigs) Settings(channel string) ChannelSettings {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	settings := cc.defaults
+	config := cc.configs[channel]
+	if config.Digest != nil {
+		settings.Digest = *config.Digest
+	}
+	if config.RequireMention != nil {
+		settings.RequireMention = *config.RequireMention
+	}
+	if config.ApproveMode != "" {
+		settings.ApproveMode = config.ApproveMode
+	}
+	if config.RequiredApprovals > 0 {
+		settings.RequiredApprovals = config.RequiredApprovals
+	}
+	if config.ListMode != "" {
+		settings.ListMode = config.ListMode
+	}
+	return settings
+}
+
+// Overridden reports whether the channel overrides key.
+func (cc *ChannelConfigs) Overridden(channel, key string) bool {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	config := cc.configs[channel]
+	switch key {
+	case "digest":
+		return config.Digest != nil
+	case "require_mention":
+		return config.RequireMention != nil
+	case "approve_mode":
+		return config.ApproveMode != ""
+	case "required_approvals":
+		return config.RequiredApprovals > 0
+	case "list_mode":
+		return config.ListMode != ""
+	}
+	return false
+}
+
+// Set overrides key for the channel. Invalid keys or values are reported as
+// a rejection.
+func (cc *ChannelConfigs) Set(channel, key, value string) error {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	config := cc.configs[channel]
+	switch key {
+	case "digest", "require_mention":
+		b, ok := parseOnOff(value)
+		if !ok {
+			return rejection(t("config.on_off", key))
+		}
+		if key == "digest" {
+			config.Digest = &b
+		} else {
+			config.RequireMention = &b
+		}
+	case "approve_mode":
+		if value != ApproveModeTag && value != ApproveModeCount {
+			return rejection(t("config.approve_mode"))
+		}
+		config.ApproveMode = value
+	case "required_approvals":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return rejection(t("config.required_approvals"))
+		}
+		config.RequiredApprovals = n
+	case "list_mode":
+		if !validListMode(value) {
+			return rejection(t("config.list_mode"))
+		}
+		config.ListMode = value
+	default:
+		return rejection(t("config.unknown", key, strings.Join(channelConfigKeys, ", ")))
+	}
+
+	cc.configs[channel] = config
+	return cc.save()
+}
+
+// Unset removes the channel's override for key.
+func (cc *ChannelConfigs) Unset(channel, key string) error {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	config := cc.configs[channel]
+	switch key {
+	case "digest":
+		config.Digest = nil
+	case "require_mention":
+		config.RequireMention = nil
+	case "approve_mode":
+		config.ApproveMode = ""
+	case "required_approvals":
+		config.RequiredApprovals = 0
+	case "list_mode":
+		config.ListMode = ""
+	default:
+		return rejection(t("config.unknown", key, strings.Join(channelConfigKeys, ", ")))
+	}
+
+	if config == (ChannelConfig{}) {
+		delete(cc.configs, channel)
+	} else {
+		cc.configs[channel] = config
+	}
+	return cc.save()
+}
+
+// Channels returns the IDs of channels with any override.
+func (cc *ChannelConfigs) Channels() []string {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	channels := make([]string, 0, len(cc.configs))
+	for channel := range cc.configs {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+	return channels
+}
+
+// save writes the overrides to disk. Callers must hold cc.mu.
+func (cc *ChannelConfigs) save() error {
+	if cc.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(cc.configs, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(cc.path, data)
+}
+
+// value renders a setting for `queue config`.
+func (s ChannelSettings) value(key string) string {
+	switch key {
+	case "digest":
+		return onOff(s.Digest)
+	case "require_mention":
+		return onOff(s.RequireMention)
+	case "approve_mode":
+		return s.ApproveMode
+	case "required_approvals":
+		return strconv.Itoa(s.RequiredApprovals)
+	case "list_mode":
+		return s.ListMode
+	}
+	return ""
+}
+
+// parseOnOff accepts on/off as well as the forms strconv.ParseBool does.
+func parseOnOff(value string) (bool, bool) {
+	switch strings.ToLower(value) {
+	case "on":
+		return true, true
+	case "off":
+		return false, true
+	}
+	b, err := strconv.ParseBool(value)
+	return b, err == nil
+}
+
+func onOff(b bool) string {
+	if b {
+		return "on"
+	}
+	return "off"
+}
+
+// setChannelConfig applies a change and reports the outcome to the user.
+func (sh *SlackHandler) setChannelConfig(ev *slackevents.MessageEvent, apply func() error, msg string) {
+	var reason rejection
+	if err := apply(); errors.As(err, &reason) {
+		sh.replyError(ev, string(reason))
+		return
+	} else if err != nil {
+		log.Printf("[ERROR] Failed to save channel config: %v", err)
+		sh.replyError(ev, t("config.save_failed"))
+		return
+	}
+	sh.API.PostMessage(ev.Channel, slack.MsgOptionText(msg, false))
+}
+
+func (sh *SlackHandler) handleQueueConfig(w http.ResponseWriter, ev *slackevents.MessageEvent) {
+	parts := strings.Fields(ev.Text)
+	switch {
+	case len(parts) == 2:
+		settings := sh.Channels.Settings(ev.Channel)
+		var b strings.Builder
+		b.WriteString(t("config.header"))
+		for _, key := range channelConfigKeys {
+			source := t("config.source_default")
+			if sh.Channels.Overridden(ev.Channel, key) {
+				source = t("config.source_channel")
+			}
+			b.WriteString(fmt.Sprintf("- `%s`: %s (%s)\n", key, settings.value(key), source))
+		}
+		sh.reply(ev, slack.MsgOptionText(b.String(), false))
+	case !sh.isAdmin(ev.User):
+		sh.replyError(ev, t("config.admin_only"))
+	case len(parts) == 5 && parts[2] == "set":
+		key, value := parts[3], parts[4]
+		sh.setChannelConfig(ev, func() error { return sh.Channels.Set(ev.Channel, key, value) },
+			t("config.set", key, value))
+	case len(parts) == 4 && parts[2] == "unset":
+		key := parts[3]
+		sh.setChannelConfig(ev, func() error { return sh.Channels.Unset(ev.Channel, key) },
+			t("config.unset", key))
+	default:
+		sh.replyError(ev, t("config.usage"))
+	}
+}
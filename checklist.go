@@ -0,0 +1,187 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// ChecklistItem is one entry of a queue's review checklist, e.g. "tests
+// added".
+type ChecklistItem struct {
+	Text      string `json:"text"`
+	Checked   bool   `json:"checked,omitempty"`
+	CheckedBy string `json:"checked_by,omitempty"` // user ID
+}
+
+// checklistProgress returns how many checklist items are checked, out of how
+// many.
+func (q *Queue) checklistProgress() (done, total int) {
+	for _, item := range q.Checklist {
+		if item.Checked {
+			done++
+		}
+	}
+	return done, len(q.Checklist)
+}
+
+// checklistDone reports whether every checklist item is checked. A queue
+// without a checklist is always done.
+func (q *Queue) checklistDone() bool {
+	done, total := q.checklistProgress()
+	return done == total
+}
+
+// completedBy reports whether an approval by the user would complete the
+// queue.
+func (q *Queue) completedBy(userID string) bool {
+	if q.RequiredApprovals > 0 {
+		return len(q.Approvers)+1 >= q.RequiredApprovals
+	}
+	return len(q.Tags) == 0 || (len(q.Tags) == 1 && q.Tags[0] == fmt.Sprintf("<@%s>", userID))
+}
+
+// renderChecklist lists the items of a checklist, numbered from 1.
+func renderChecklist(items []ChecklistItem) string {
+	var b strings.Builder
+	for i, item := range items {
+		box := ":white_square:"
+		if item.Checked {
+			box = ":ballot_box_with_check:"
+		}
+		b.WriteString(fmt.Sprintf("%s %d. %s\n", box, i+1, item.Text))
+	}
+	return b.String()
+}
+
+// checklistArgs is a parsed "queue checklist" command.
+type checklistArgs struct {
+	ref   string
+	verb  string // "", "add", "remove", "check" or "uncheck"
+	text  string // item text for add
+	index int    // zero-based item for the other verbs
+}
+
+// parseChecklistArgs parses "queue checklist <id> [add <item> | remove <n> |
+// check <n> | uncheck <n>]".
+func (sh *SlackHandler) parseChecklistArgs(command string) (checklistArgs, error) {
+	parts := splitArgs(command)
+	if len(parts) < 3 {
+		return checklistArgs{}, errors.New(t("checklist.usage"))
+	}
+	if !sh.validQueueID(parts[2]) {
+		return checklistArgs{}, errors.New(t("queue.invalid_id"))
+	}
+	args := checklistArgs{ref: parts[2]}
+	if len(parts) == 3 {
+		return args, nil
+	}
+
+	args.verb = strings.ToLower(parts[3])
+	switch args.verb {
+	case "add":
+		args.text = strings.TrimSpace(strings.Join(parts[4:], " "))
+		if args.text == "" {
+			return checklistArgs{}, errors.New(t("checklist.usage"))
+		}
+	case "remove", "check", "uncheck":
+		if len(parts) != 5 {
+			return checklistArgs{}, errors.New(t("checklist.usage"))
+		}
+		n, err := strconv.Atoi(parts[4])
+		if err != nil || n < 1 {
+			return checklistArgs{}, errors.New(t("checklist.invalid_item", parts[4]))
+		}
+		args.index = n - 1
+	default:
+		return checklistArgs{}, errors.New(t("checklist.usage"))
+	}
+	return args, nil
+}
+
+// canCheck reports whether the user may tick items off the queue's checklist:
+// its owner, its reviewers and admins.
+func (sh *SlackHandler) canCheck(q *Queue, userID string) bool {
+	tag := fmt.Sprintf("<@%s>", userID)
+	return q.Owner == userID || q.hasTag(tag) || slices.Contains(q.Approvers, tag) || sh.isAdmin(userID)
+}
+
+// handleQueueChecklist shows a queue's review checklist, or edits it. Owners
+// and admins manage the items; reviewers can also check them off.
+func (sh *SlackHandler) handleQueueChecklist(w http.ResponseWriter, ev *slackevents.MessageEvent) {
+	args, err := sh.parseChecklistArgs(ev.Text)
+	if err != nil {
+		sh.replyError(ev, err.Error())
+		return
+	}
+
+	sh.mu.Lock()
+	queue, err := sh.findQueue(args.ref)
+	if err == nil && args.verb != "" {
+		queue, err = sh.Store.Update(queue.ID, func(q *Queue) error {
+			return sh.editChecklist(q, args, ev.User)
+		})
+		if err == nil {
+			sh.refreshStatusCard(queue)
+		}
+	}
+	if err == nil {
+		queue = queue.clone()
+	}
+	sh.mu.Unlock()
+
+	if err != nil {
+		sh.replyQueueError(ev, err)
+		return
+	}
+	if len(queue.Checklist) == 0 {
+		sh.replyError(ev, t("checklist.empty", queue.DisplayID()))
+		return
+	}
+	done, total := queue.checklistProgress()
+	msg := t("checklist.header", queue.DisplayID(), queue.Title, done, total) + renderChecklist(queue.Checklist)
+	sh.API.PostMessage(ev.Channel, slack.MsgOptionText(msg, false))
+}
+
+// editChecklist applies a checklist edit to the queue. Callers must hold
+// sh.mu.
+func (sh *SlackHandler) editChecklist(q *Queue, args checklistArgs, userID string) error {
+	if err := q.checkActive(); err != nil {
+		return err
+	}
+	switch args.verb {
+	case "add", "remove":
+		if q.Owner != userID && !sh.isAdmin(userID) {
+			return rejection(t("checklist.owner_only", q.Owner))
+		}
+	default:
+		if !sh.canCheck(q, userID) {
+			return rejection(t("checklist.reviewers_only"))
+		}
+	}
+	if args.verb != "add" && args.index >= len(q.Checklist) {
+		return rejection(t("checklist.no_item", args.index+1, q.DisplayID()))
+	}
+
+	switch args.verb {
+	case "add":
+		q.Checklist = append(q.Checklist, ChecklistItem{Text: sanitize(args.text, sh.isAdmin(userID))})
+	case "remove":
+		q.Checklist = slices.Delete(q.Checklist, args.index, args.index+1)
+	case "check":
+		q.Checklist[args.index].Checked = true
+		q.Checklist[args.index].CheckedBy = userID
+	case "uncheck":
+		q.Checklist[args.index].Checked = false
+		q.Checklist[args.index].CheckedBy = ""
+	}
+	q.UpdatedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,392 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// Command describes a bot command. The registry drives both dispatch in
+// handleCallbackEvent and the output of handleQueueHelp.
+type Command struct {
+	// Name is the full invocation, e.g. "queue add".
+	Name string
+	// Aliases are alternative invocations rewritten to Name before dispatch.
+	Aliases     []string
+	Usage       string
+	Description string
+	Example     string
+	Handler     func(sh *SlackHandler, w http.ResponseWriter, ev *slackevents.MessageEvent)
+}
+
+// description returns the command's description in the current locale.
+// Descriptions are written here in English; other locales translate them
+// under "help.<subcommand>".
+func (cmd *Command) description() string {
+	key := "help." + strings.TrimPrefix(cmd.Name, defaultCommandPrefix+" ")
+	if text, ok := catalog[locale][key]; ok {
+		return text
+	}
+	return cmd.Description
+}
+
+// defaultCommands returns the command registry in the order shown by help.
+func defaultCommands() []Command {
+	return []Command{
+		{
+			Name:        "queue add",
+			Usage:       `queue add <title> <link> @tag @tag... [label...] [--desc "description"] [--sla=4h | --due "tomorrow 5pm"] [--size=S|M|L|XL] [--dry-run]`,
+			Description: "Adds a queue with a title, link, reviewer tags (user mentions), optional labels (any other words, e.g. hotfix), an optional description, an optional review SLA or due date (in your timezone), and an optional change size (S, M, L, XL or a line count) so small reviews stand out. The link may be a bare !123 or #123 when GITLAB_MR_BASE_URL or GITHUB_PR_BASE_URL is set. Without tags, a reviewer is picked from the reviewer pool; if the pool is empty, at least one tag is required. --dry-run only shows what would be added",
+			Example:     `queue add "New Feature" https://example.com @user1 @user2 backend --desc "Adds the export button"`,
+			Handler:     (*SlackHandler).handleQueueAdd,
+		},
+		{
+			Name:        "queue list",
+			Aliases:     []string{"queue ls"},
+			Usage:       "queue list [compact|full] [label <label>] [sort=id|age|priority] [--archived] [--json]",
+			Description: "Lists the queues in this channel, optionally only those with a label, by ID (default), age (oldest first) or priority (overdue, then due soonest, then oldest). compact shows only IDs and titles, full the details; the channel's list_mode setting picks the default. --archived lists archived queues instead. --json posts them as a JSON array for scripts",
+			Handler:     (*SlackHandler).handleQueueList,
+		},
+		{
+			Name:        "queue info",
+			Usage:       "queue info <queueID>",
+			Description: "Shows every detail of a queue: link, description, owner, reviewers and approvals, labels, due date, and review claim",
+			Handler:     (*SlackHandler).handleQueueInfo,
+		},
+		{
+			Name:        "queue remove",
+			Aliases:     []string{"queue rm", "queue del"},
+			Usage:       "queue remove <queueID> [<queueID>...] [--yes]",
+			Description: "Removes one or more queues by ID or MR link. When removal confirmation is enabled, add --yes to confirm",
+			Handler:     (*SlackHandler).handleQueueRemove,
+		},
+		{
+			Name:        "queue clear",
+			Usage:       "queue clear [--all] --yes",
+			Description: "Removes every queue in this channel, or in all channels with --all (admin only). Without --yes it only says how many would be removed",
+			Handler:     (*SlackHandler).handleQueueClear,
+		},
+		{
+			Name:        "queue undo",
+			Usage:       "queue undo",
+			Description: "Restores the queues removed by the most recent `queue remove` in this channel (within 5 minutes; remover or admin only)",
+			Handler:     (*SlackHandler).handleQueueUndo,
+		},
+		{
+			Name:        "queue approve",
+			Aliases:     []string{"queue ok"},
+			Usage:       "queue approve <queueID> [<queueID>...]",
+			Description: "Approves one or more queues by ID or MR link. In tag mode (the default) your tag is removed and the queue completes when no tags are left; in count mode anyone may approve once and the queue completes at the required number of approvals. Owners can't approve their own queues unless ALLOW_SELF_APPROVE is set. Reacting to a queue's message with :white_check_mark: also approves it (removing the reaction withdraws the approval), and :x: requests changes",
+			Handler:     (*SlackHandler).handleQueueApprove,
+		},
+		{
+			Name:        "queue review",
+			Usage:       "queue review <queueID>",
+			Description: "Claims a queue for review. The queue may be given by its MR link instead of its ID",
+			Handler:     (*SlackHandler).handleQueueReview,
+		},
+		{
+			Name:        "queue take",
+			Usage:       "queue take <queueID>",
+			Description: "Tags you on a queue and claims it for review in one step",
+			Handler:     (*SlackHandler).handleQueueTake,
+		},
+		{
+			Name:        "queue next",
+			Usage:       "queue next",
+			Description: "Recommends the most urgent unclaimed queue in this channel for you to pick up, with a button to take it",
+			Handler:     (*SlackHandler).handleQueueNext,
+		},
+		{
+			Name:        "queue update",
+			Usage:       "queue update <queueID>",
+			Description: "Releases the review claim on a queue, returning it to open (reviewer or admin only)",
+			Handler:     (*SlackHandler).handleQueueUpdate,
+		},
+		{
+			Name:        "queue close",
+			Usage:       "queue close <queueID>",
+			Description: "Closes a queue without removing it (owner or admin only)",
+			Handler:     (*SlackHandler).handleQueueClose,
+		},
+		{
+			Name:        "queue archive",
+			Usage:       "queue archive <queueID>",
+			Description: "Archives an approved or closed queue, keeping it as a record out of the default list (owner or admin only)",
+			Handler:     (*SlackHandler).handleQueueArchive,
+		},
+		{
+			Name:        "queue ping",
+			Usage:       "queue ping <queueID>",
+			Description: "Reminds the pending reviewers of a queue now (at most once every 10 minutes per queue)",
+			Handler:     (*SlackHandler).handleQueuePing,
+		},
+		{
+			Name:        "queue checklist",
+			Usage:       "queue checklist <queueID> [add \"item\" | remove <n> | check <n> | uncheck <n>]",
+			Description: "Shows or edits a queue's review checklist. The owner and admins add and remove items; reviewers can also check them off. Unless REQUIRE_CHECKLIST is false, a queue can't be fully approved until every item is checked",
+			Example:     "queue checklist 3 add \"tests added\"",
+			Handler:     (*SlackHandler).handleQueueChecklist,
+		},
+		{
+			Name:        "queue snooze",
+			Usage:       "queue snooze <queueID> <duration|off>",
+			Description: "Pauses overdue reminders for a queue for a while, e.g. 2h or 1d, or resumes them with off (owner or admin only)",
+			Example:     "queue snooze 3 2h",
+			Handler:     (*SlackHandler).handleQueueSnooze,
+		},
+		{
+			Name:        "queue assign",
+			Usage:       "queue assign <queueID> @user",
+			Description: "Adds a reviewer to an existing queue and notifies them",
+			Handler:     (*SlackHandler).handleQueueAssign,
+		},
+		{
+			Name:        "queue unassign",
+			Usage:       "queue unassign <queueID> @user",
+			Description: "Removes a pending reviewer from a queue",
+			Handler:     (*SlackHandler).handleQueueUnassign,
+		},
+		{
+			Name:        "queue tags",
+			Usage:       "queue tags <queueID> set|add|remove @user [@user...]",
+			Description: "Replaces, extends or trims the pending reviewers of a queue (owner or admin only)",
+			Handler:     (*SlackHandler).handleQueueTags,
+		},
+		{
+			Name:        "queue reassign",
+			Usage:       "queue reassign <queueID> @user [@user...]",
+			Description: "Replaces all reviewers of a queue and clears its approvals, notifying the removed and added reviewers (owner or admin only)",
+			Handler:     (*SlackHandler).handleQueueReassign,
+		},
+		{
+			Name:        "queue owner",
+			Usage:       "queue owner <queueID> @user",
+			Description: "Transfers a queue to a new owner and notifies them (owner or admin only)",
+			Handler:     (*SlackHandler).handleQueueOwner,
+		},
+		{
+			Name:        "queue move",
+			Usage:       "queue move <queueID> #channel",
+			Description: "Moves a queue to another channel the bot is a member of",
+			Handler:     (*SlackHandler).handleQueueMove,
+		},
+		{
+			Name:        "queue desc",
+			Usage:       `queue desc <queueID> "description"`,
+			Description: "Sets or clears the description of a queue",
+			Handler:     (*SlackHandler).handleQueueDesc,
+		},
+		{
+			Name:        "queue stats",
+			Usage:       "queue stats [me] [window]",
+			Description: "Shows created/approved/removed counts, review latency, and the top reviewers (default window 7d). With me, shows only you your own approvals, average turnaround, and assigned and overdue queues",
+			Handler:     (*SlackHandler).handleQueueStats,
+		},
+		{
+			Name:        "queue digest",
+			Usage:       "queue digest on|off",
+			Description: "Opts this channel in or out of the scheduled review digest",
+			Handler:     (*SlackHandler).handleQueueDigest,
+		},
+		{
+			Name:        "queue config",
+			Usage:       "queue config | queue config set|unset <key> [value]",
+			Description: "Shows this channel's settings, or overrides one (admin only). Keys: digest, require_mention, approve_mode, required_approvals, list_mode; channels without an override use the defaults from the environment",
+			Handler:     (*SlackHandler).handleQueueConfig,
+		},
+		{
+			Name:        "queue help",
+			Usage:       "queue help [command]",
+			Description: "Displays this help message (also shown for a bare `queue`), or the details of one command, e.g. `queue help add`",
+			Handler:     (*SlackHandler).handleQueueHelp,
+		},
+		{
+			Name:        "queue whoami",
+			Usage:       "queue whoami",
+			Description: "Shows your Slack user ID and the tag approvals are matched against, to troubleshoot \"tag not found\"",
+			Handler:     (*SlackHandler).handleQueueWhoami,
+		},
+		{
+			Name:        "queue version",
+			Usage:       "queue version",
+			Description: "Shows the version, git commit and Go version of the running bot",
+			Handler:     (*SlackHandler).handleQueueVersion,
+		},
+		{
+			Name:        "reviewers",
+			Usage:       "reviewers list | reviewers add|remove @user",
+			Description: "Lists the reviewer pool, or adds/removes a reviewer (admin only)",
+			Handler:     (*SlackHandler).handleReviewers,
+		},
+	}
+}
+
+// matchCommand reports whether text invokes name, i.e. starts with it and is
+// followed by whitespace or nothing.
+func matchCommand(text, name string) (string, bool) {
+	rest, ok := strings.CutPrefix(text, name)
+	if !ok || (rest != "" && !unicode.IsSpace(rune(rest[0]))) {
+		return "", false
+	}
+	return rest, true
+}
+
+// defaultCommandPrefix is the prefix commands are registered under.
+const defaultCommandPrefix = "queue"
+
+// validCommandPrefix reports whether prefix can be used as COMMAND_PREFIX.
+func validCommandPrefix(prefix string) bool {
+	return prefix != "" && !strings.ContainsFunc(prefix, unicode.IsSpace)
+}
+
+// withPrefix rewrites command references in user-facing text, such as
+// "`queue undo`" or "Usage: queue add", to the configured prefix.
+func (sh *SlackHandler) withPrefix(text string) string {
+	if sh.CommandPrefix == defaultCommandPrefix {
+		return text
+	}
+	if rest, ok := matchCommand(text, defaultCommandPrefix); ok {
+		text = sh.CommandPrefix + rest
+	}
+	return strings.NewReplacer(
+		"`"+defaultCommandPrefix+" ", "`"+sh.CommandPrefix+" ",
+		"Usage: "+defaultCommandPrefix+" ", "Usage: "+sh.CommandPrefix+" ",
+	).Replace(text)
+}
+
+// lookupCommand finds the registered command invoked by text, rewriting the
+// configured prefix and any alias to the canonical name. A bare prefix
+// resolves to help.
+func (sh *SlackHandler) lookupCommand(text string) (*Command, string) {
+	rest, ok := matchCommand(text, sh.CommandPrefix)
+	if !ok && sh.CommandPrefix != defaultCommandPrefix {
+		if _, ok := matchCommand(text, defaultCommandPrefix); ok {
+			// Leave "queue ..." to whichever bot owns that prefix
+			return nil, text
+		}
+	}
+	if ok {
+		text = defaultCommandPrefix + rest
+	}
+	if text == defaultCommandPrefix {
+		text = defaultCommandPrefix + " help"
+	}
+
+	for i := range sh.commands {
+		cmd := &sh.commands[i]
+		for _, name := range append([]string{cmd.Name}, cmd.Aliases...) {
+			if rest, ok := matchCommand(text, name); ok {
+				return cmd, cmd.Name + rest
+			}
+		}
+	}
+	return nil, text
+}
+
+// maxSuggestionDistance is the largest edit distance at which a mistyped
+// subcommand gets a "did you mean" suggestion.
+const maxSuggestionDistance = 2
+
+// suggestCommand returns the registered command closest to the mistyped
+// subcommand in command, e.g. "queue approve" for "queue aprove 3", or "" if
+// none is close.
+func (sh *SlackHandler) suggestCommand(command string) string {
+	parts := strings.Fields(command)
+	if len(parts) < 2 || parts[0] != defaultCommandPrefix {
+		return ""
+	}
+
+	best, bestDistance := "", maxSuggestionDistance+1
+	for _, cmd := range sh.commands {
+		for _, name := range append([]string{cmd.Name}, cmd.Aliases...) {
+			sub, ok := strings.CutPrefix(name, defaultCommandPrefix+" ")
+			if !ok {
+				continue
+			}
+			// Short aliases like "ok" would match almost anything
+			if d := levenshtein(parts[1], sub); d < bestDistance && d < len(sub) {
+				best, bestDistance = cmd.Name, d
+			}
+		}
+	}
+	return best
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// helpTopic finds the command named by a help argument such as "add", "rm"
+// or "reviewers".
+func (sh *SlackHandler) helpTopic(topic string) *Command {
+	for i := range sh.commands {
+		cmd := &sh.commands[i]
+		for _, name := range append([]string{cmd.Name}, cmd.Aliases...) {
+			if name == topic || name == defaultCommandPrefix+" "+topic {
+				return cmd
+			}
+		}
+	}
+	return nil
+}
+
+// commandHelp details a single command.
+func (sh *SlackHandler) commandHelp(cmd *Command) string {
+	var help strings.Builder
+	help.WriteString(sh.withPrefix(fmt.Sprintf("`%s`\n%s\n", cmd.Usage, cmd.description())))
+	if cmd.Example != "" {
+		help.WriteString(sh.withPrefix(fmt.Sprintf("%s: `%s`\n", t("help.example"), cmd.Example)))
+	}
+	if len(cmd.Aliases) > 0 {
+		help.WriteString(sh.withPrefix(fmt.Sprintf("%s: `%s`\n", t("help.aliases"), strings.Join(cmd.Aliases, "`, `"))))
+	}
+	return help.String()
+}
+
+func (sh *SlackHandler) handleQueueHelp(w http.ResponseWriter, ev *slackevents.MessageEvent) {
+	if parts := strings.Fields(ev.Text); len(parts) > 2 {
+		if cmd := sh.helpTopic(strings.Join(parts[2:], " ")); cmd != nil {
+			sh.reply(ev, slack.MsgOptionText(sh.commandHelp(cmd), false))
+			return
+		}
+	}
+
+	// Without a known command, list them all
+	var help strings.Builder
+	help.WriteString(t("help.header"))
+	for _, cmd := range sh.commands {
+		help.WriteString(sh.withPrefix(fmt.Sprintf("- `%s`: %s\n", cmd.Usage, cmd.description())))
+		if cmd.Example != "" {
+			help.WriteString(sh.withPrefix(fmt.Sprintf("  %s: `%s`\n", t("help.example"), cmd.Example)))
+		}
+		if len(cmd.Aliases) > 0 {
+			help.WriteString(sh.withPrefix(fmt.Sprintf("  %s: `%s`\n", t("help.aliases"), strings.Join(cmd.Aliases, "`, `"))))
+		}
+	}
+
+	// Send the help message to the Slack channel
+	sh.reply(ev, slack.MsgOptionText(help.String(), false))
+}
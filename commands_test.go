@@ -0,0 +1,129 @@
+package main
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/slack-go/slack/slackevents"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"approve", "approve", 0},
+		{"aprove", "approve", 1},
+		{"approve", "aprrove", 1},
+		{"reveiw", "review", 2},
+		{"", "list", 4},
+		{"kitten", "sitting", 3},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSuggestCommand(t *testing.T) {
+	sh, _ := newTestHandler(t, nil)
+	tests := []struct {
+		command string
+		want    string
+	}{
+		{"queue aprove 3", "queue approve"},
+		{"queue approv", "queue approve"},
+		{"queue reveiw 2", "queue review"},
+		{"queue remvoe 1", "queue remove"},
+		{"queue frobnicate", ""},
+		{"queue xyzzy 3", ""},
+		{"queue", ""},
+		{"deploy aprove", ""},
+	}
+	for _, tt := range tests {
+		if got := sh.suggestCommand(tt.command); got != tt.want {
+			t.Errorf("suggestCommand(%q) = %q, want %q", tt.command, got, tt.want)
+		}
+	}
+}
+
+func TestDidYouMeanReply(t *testing.T) {
+	sh, api := newTestHandler(t, nil)
+
+	command(sh, "U1", "queue aprove 3")
+	if got := api.last(); got.User != "U1" || !strings.Contains(got.Text, "Did you mean `queue approve`?") {
+		t.Errorf("close input: reply %+v", got)
+	}
+	command(sh, "U1", "queue frobnicate 3")
+	if got := api.last(); got.User != "U1" || strings.Contains(got.Text, "Did you mean") {
+		t.Errorf("far-off input: reply %+v, want no suggestion", got)
+	}
+}
+
+// truncatedArgs are argument lists that stop short, misalign or are
+// malformed, appended to every command name by TestTruncatedCommands.
+var truncatedArgs = []string{
+	"", " ", " 1", ` "`, ` ""`, ` "" ""`, ` 1 ""`, ` 1 "unterminated`,
+	" --", " --yes", " --all", " --all --yes", " --desc", " --desc=", " --due", " --sla=", " --size=XXL",
+	" <@U2>", " 1 <@U2>", " 1 --yes", " 1 #", " 1 <#C2>", " 1 <#C2|", " 1 off", " 1 2h", " 1 forever",
+	" 1 add", ` 1 add "`, " 1 set", " 1 set <@U2>", " 1 remove", " 1 check", " 1 check x", " 1 uncheck 0", " 1 check 99",
+	" me", " me 7d", " label", " sort=", " compact label", " set", " set key", " unset", " on",
+	" list", " add", " remove", " add <@U2>", " 0", " -1", " 99999999999999999999", " x y z w v",
+}
+
+// TestTruncatedCommands runs every registered command with arguments that
+// stop short or are malformed. None may panic, and a command missing the
+// queue ID it requires must say so.
+func TestTruncatedCommands(t *testing.T) {
+	for _, cmd := range defaultCommands() {
+		for _, name := range append([]string{cmd.Name}, cmd.Aliases...) {
+			inputs := make([]string, 0, len(truncatedArgs))
+			for _, args := range truncatedArgs {
+				inputs = append(inputs, name+args)
+			}
+			// Every prefix of the example, e.g. "queue checklist 3 add"
+			fields := strings.Fields(cmd.Example)
+			for i := 1; i < len(fields); i++ {
+				inputs = append(inputs, strings.Join(fields[:i], " "))
+			}
+
+			t.Run(name, func(t *testing.T) {
+				for _, admin := range []bool{false, true} {
+					for _, input := range inputs {
+						sh, api := newTestHandler(t, func(cfg *Config) {
+							cfg.ThreadScoped = true
+							if admin {
+								cfg.Admins = []string{"U1"}
+							}
+						})
+						command(sh, "U1", `queue add "Feature" https://example.com/mr/1 <@U2>`)
+						api.reset()
+
+						func() {
+							defer func() {
+								if r := recover(); r != nil {
+									t.Errorf("%q (admin %v) panicked: %v", input, admin, r)
+								}
+							}()
+							command(sh, "U1", input)
+							// In the queue's thread, where the ID may be filled in
+							sh.dispatchCommand(httptest.NewRecorder(), &slackevents.MessageEvent{
+								User:            "U1",
+								Channel:         "C1",
+								Text:            input,
+								TimeStamp:       "1000.000009",
+								ThreadTimeStamp: "1000.000001",
+							})
+						}()
+						if input == name && strings.HasPrefix(cmd.Usage, cmd.Name+" <") && len(api.sent()) == 0 {
+							t.Errorf("%q without arguments sent no reply", input)
+						}
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Config holds the bot settings read from the environment.
+type Config struct {
+	BotToken         string
+	SigningSecret    string
+	Admins           []string
+	AllowedChannels  []string
+	ReviewerPoolPath string
+	DigestCron       string
+	EventsPath       string
+	ChannelsPath     string
+	WebhookURL       string
+	DatabaseURL      string
+	RedisURL         string
+	RequireMention   bool
+	IDFormat         string
+	IDPrefixes       map[string]string
+	MaxBodyBytes     int
+	ConfirmRemove    bool
+	CommandPrefix    string
+	PinReviews       bool
+	StatusCards      bool
+	ReprocessEdits   bool
+	AllowSelfApprove bool
+	ThreadScoped     bool
+	AutoArchive      bool
+	ApproveMode      string
+	ListMode         string
+	Locale           string
+	Capacity         CapacityConfig
+	MRLinks          MRLinkConfig
+	TextLimits       TextLimits
+	Reactions        ReactionConfig
+	Reminders        ReminderConfig
+	Snapshot         SnapshotConfig
+	AuthRefresh      time.Duration
+	APIKey           string
+	OAuth            OAuthConfig
+
+	// RequiredApprovals completes a queue in ApproveModeCount.
+	RequiredApprovals int
+	// NotifyBatchWindow coalesces DMs to the same user sent within it.
+	NotifyBatchWindow time.Duration
+	// RequireChecklist blocks completing a queue with unchecked items.
+	RequireChecklist bool
+	// AutoArchiveAfter archives approved queues once they have been approved
+	// this long; zero leaves them to AutoArchive or manual archiving.
+	AutoArchiveAfter time.Duration
+}
+
+// LoadConfig reads the bot settings from the environment, applying defaults
+// for anything unset.
+func LoadConfig() (*Config, error) {
+	cfg := &Config{
+		BotToken:         os.Getenv("SLACK_BOT_TOKEN"),
+		SigningSecret:    os.Getenv("SLACK_SIGNING_SECRET"),
+		Admins:           splitList(os.Getenv("ADMIN_USERS")),
+		AllowedChannels:  splitList(os.Getenv("ALLOWED_CHANNELS")),
+		ReviewerPoolPath: envString("REVIEWER_POOL_PATH", "reviewers.json"),
+		DigestCron:       os.Getenv("DIGEST_CRON"),
+		EventsPath:       envString("EVENTS_PATH", "/events-endpoint"),
+		ChannelsPath:     envString("CHANNEL_CONFIG_PATH", "channel_config.json"),
+		WebhookURL:       os.Getenv("OUTGOING_WEBHOOK_URL"),
+		APIKey:           os.Getenv("API_KEY"),
+		DatabaseURL:      os.Getenv("DATABASE_URL"),
+		RedisURL:         os.Getenv("REDIS_URL"),
+		IDFormat:         envString("ID_FORMAT", IDFormatNumeric),
+		CommandPrefix:    envString("COMMAND_PREFIX", defaultCommandPrefix),
+		ApproveMode:      envString("APPROVE_MODE", ApproveModeTag),
+		ListMode:         envString("LIST_MODE", ListModeFull),
+		Locale:           envString("LOCALE", LocaleEnglish),
+		IDPrefixes:       make(map[string]string),
+		Capacity: CapacityConfig{
+			Mode: envString("OVER_CAPACITY", OverCapacityBlock),
+		},
+		MRLinks: MRLinkConfig{
+			GitLabBaseURL: os.Getenv("GITLAB_MR_BASE_URL"),
+			GitHubBaseURL: os.Getenv("GITHUB_PR_BASE_URL"),
+		},
+		Reactions: ReactionConfig{
+			Approve:        envReactions("APPROVE_REACTIONS", "white_check_mark,heavy_check_mark"),
+			RequestChanges: envReactions("CHANGES_REACTIONS", "x"),
+		},
+		Reminders: ReminderConfig{
+			EscalationTarget: os.Getenv("ESCALATION_USER"),
+		},
+		Snapshot: SnapshotConfig{
+			Path: os.Getenv("SNAPSHOT_PATH"),
+		},
+		OAuth: OAuthConfig{
+			ClientID:     os.Getenv("SLACK_CLIENT_ID"),
+			ClientSecret: os.Getenv("SLACK_CLIENT_SECRET"),
+			RedirectURL:  os.Getenv("SLACK_REDIRECT_URL"),
+			TokensPath:   envString("TEAM_TOKENS_PATH", "team_tokens.json"),
+		},
+	}
+
+	if cfg.BotToken == "" {
+		return nil, fmt.Errorf("SLACK_BOT_TOKEN is required")
+	}
+	if cfg.SigningSecret == "" {
+		return nil, fmt.Errorf("SLACK_SIGNING_SECRET is required; without it request signatures cannot be verified")
+	}
+	if cfg.OAuth.ClientID != "" && cfg.OAuth.ClientSecret == "" {
+		return nil, fmt.Errorf("SLACK_CLIENT_SECRET is required when SLACK_CLIENT_ID is set")
+	}
+	if cfg.DatabaseURL != "" && cfg.RedisURL != "" {
+		return nil, fmt.Errorf("DATABASE_URL and REDIS_URL are mutually exclusive")
+	}
+	if !validCommandPrefix(cfg.CommandPrefix) {
+		return nil, fmt.Errorf("invalid COMMAND_PREFIX %q: must be a single word", cfg.CommandPrefix)
+	}
+	if cfg.DigestCron != "" {
+		if _, err := cron.ParseStandard(cfg.DigestCron); err != nil {
+			return nil, fmt.Errorf("invalid DIGEST_CRON %q: %v", cfg.DigestCron, err)
+		}
+	}
+	if cfg.ApproveMode != ApproveModeTag && cfg.ApproveMode != ApproveModeCount {
+		return nil, fmt.Errorf("invalid APPROVE_MODE %q: expected tag or count", cfg.ApproveMode)
+	}
+	if !validListMode(cfg.ListMode) {
+		return nil, fmt.Errorf("invalid LIST_MODE %q: expected full or compact", cfg.ListMode)
+	}
+	if !strings.HasPrefix(cfg.EventsPath, "/") || strings.ContainsFunc(cfg.EventsPath, unicode.IsSpace) {
+		return nil, fmt.Errorf("invalid EVENTS_PATH %q: must start with / and contain no spaces", cfg.EventsPath)
+	}
+	if reservedPath(cfg.EventsPath) {
+		return nil, fmt.Errorf("invalid EVENTS_PATH %q: the server already uses this path", cfg.EventsPath)
+	}
+	for name, base := range map[string]string{"GITLAB_MR_BASE_URL": cfg.MRLinks.GitLabBaseURL, "GITHUB_PR_BASE_URL": cfg.MRLinks.GitHubBaseURL} {
+		if base != "" && !looksLikeURL(base) {
+			return nil, fmt.Errorf("invalid %s %q: expected an http(s) URL", name, base)
+		}
+	}
+	if cfg.Capacity.Mode != OverCapacityBlock && cfg.Capacity.Mode != OverCapacitySkip {
+		return nil, fmt.Errorf("invalid OVER_CAPACITY %q: expected block or skip", cfg.Capacity.Mode)
+	}
+	if !validIDFormat(cfg.IDFormat) {
+		return nil, fmt.Errorf("invalid ID_FORMAT %q: expected numeric, prefixed or short", cfg.IDFormat)
+	}
+	// ID_PREFIXES maps channels to prefixes, e.g. "C0123=ENG,C0456=OPS"
+	for _, pair := range splitList(os.Getenv("ID_PREFIXES")) {
+		channel, prefix, ok := strings.Cut(pair, "=")
+		if !ok || channel == "" || prefix == "" {
+			return nil, fmt.Errorf("invalid ID_PREFIXES entry %q: expected CHANNEL=PREFIX", pair)
+		}
+		cfg.IDPrefixes[channel] = strings.ToUpper(prefix)
+	}
+
+	var err error
+	if cfg.RequireMention, err = envBool("REQUIRE_MENTION", false); err != nil {
+		return nil, err
+	}
+	if cfg.MaxBodyBytes, err = envInt("MAX_BODY_BYTES", 1<<20); err != nil {
+		return nil, err
+	}
+	if cfg.MaxBodyBytes == 0 {
+		return nil, fmt.Errorf("invalid MAX_BODY_BYTES 0: must be positive")
+	}
+	if cfg.ConfirmRemove, err = envBool("CONFIRM_REMOVE", false); err != nil {
+		return nil, err
+	}
+	if cfg.PinReviews, err = envBool("PIN_REVIEWS", false); err != nil {
+		return nil, err
+	}
+	if cfg.StatusCards, err = envBool("STATUS_CARDS", false); err != nil {
+		return nil, err
+	}
+	if cfg.ReprocessEdits, err = envBool("REPROCESS_EDITS", false); err != nil {
+		return nil, err
+	}
+	if cfg.AllowSelfApprove, err = envBool("ALLOW_SELF_APPROVE", false); err != nil {
+		return nil, err
+	}
+	if cfg.ThreadScoped, err = envBool("THREAD_SCOPED", false); err != nil {
+		return nil, err
+	}
+	if cfg.AutoArchive, err = envBool("AUTO_ARCHIVE", false); err != nil {
+		return nil, err
+	}
+	if cfg.RequiredApprovals, err = envInt("REQUIRED_APPROVALS", 1); err != nil {
+		return nil, err
+	}
+	if cfg.RequiredApprovals == 0 {
+		return nil, fmt.Errorf("invalid REQUIRED_APPROVALS 0: must be at least 1")
+	}
+	if cfg.TextLimits.MinTitle, err = envInt("MIN_TITLE_LENGTH", 0); err != nil {
+		return nil, err
+	}
+	if cfg.TextLimits.MinDescription, err = envInt("MIN_DESCRIPTION_LENGTH", 0); err != nil {
+		return nil, err
+	}
+	if cfg.Capacity.MaxPerReviewer, err = envInt("MAX_PER_REVIEWER", 0); err != nil {
+		return nil, err
+	}
+	if cfg.Reminders.Interval, err = envDuration("REMINDER_INTERVAL", 30*time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.Reminders.EscalateAfter, err = envInt("ESCALATE_AFTER", 3); err != nil {
+		return nil, err
+	}
+	if cfg.Reminders.Template, err = parseReminderTemplate(envString("REMINDER_TEMPLATE", defaultReminderTemplate)); err != nil {
+		log.Printf("[WARN] Invalid REMINDER_TEMPLATE, using the default: %v", err)
+		cfg.Reminders.Template = defaultReminder
+	}
+	if cfg.Snapshot.Interval, err = envDuration("SNAPSHOT_INTERVAL", time.Minute); err != nil {
+		return nil, err
+	}
+	if cfg.AuthRefresh, err = envDuration("AUTH_REFRESH_INTERVAL", time.Hour); err != nil {
+		return nil, err
+	}
+	if cfg.NotifyBatchWindow, err = envDuration("NOTIFY_BATCH_WINDOW", 30*time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.RequireChecklist, err = envBool("REQUIRE_CHECKLIST", true); err != nil {
+		return nil, err
+	}
+	if cfg.AutoArchiveAfter, err = envDuration("AUTOARCHIVE_AFTER", 0); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// ChannelDefaults returns the settings for channels without an override.
+func (cfg *Config) ChannelDefaults() ChannelSettings {
+	return ChannelSettings{
+		RequireMention:    cfg.RequireMention,
+		ApproveMode:       cfg.ApproveMode,
+		RequiredApprovals: cfg.RequiredApprovals,
+		ListMode:          cfg.ListMode,
+	}
+}
+
+func envString(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// envReactions reads a list of reaction names. Unlike envString, an empty
+// value is kept, which turns those reactions off.
+func envReactions(key, fallback string) []string {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		value = fallback
+	}
+	return parseReactions(value)
+}
+
+func envBool(key string, fallback bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s %q: expected true or false", key, value)
+	}
+	return b, nil
+}
+
+func envInt(key string, fallback int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s %q: expected a non-negative integer", key, value)
+	}
+	return n, nil
+}
+
+func envDuration(key string, fallback time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %v", key, value, err)
+	}
+	return d, nil
+}
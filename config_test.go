@@ -0,0 +1,66 @@
+package main
+
+import "testing"
+
+func TestLoadConfigMaxBodyBytes(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int
+		ok    bool
+	}{
+		{"", 1 << 20, true},
+		{"2048", 2048, true},
+		{"0", 0, false},
+		{"-1", 0, false},
+		{"lots", 0, false},
+	}
+	for _, tt := range tests {
+		t.Setenv("SLACK_BOT_TOKEN", "xoxb-test")
+		t.Setenv("SLACK_SIGNING_SECRET", testSigningSecret)
+		t.Setenv("MAX_BODY_BYTES", tt.value)
+
+		cfg, err := LoadConfig()
+		if (err == nil) != tt.ok {
+			t.Errorf("MAX_BODY_BYTES=%q: err = %v, want ok %v", tt.value, err, tt.ok)
+			continue
+		}
+		if err == nil && cfg.MaxBodyBytes != tt.want {
+			t.Errorf("MAX_BODY_BYTES=%q: got %d, want %d", tt.value, cfg.MaxBodyBytes, tt.want)
+		}
+	}
+}
+
+func TestLoadConfigEventsPath(t *testing.T) {
+	tests := []struct {
+		value string
+		ok    bool
+	}{
+		{"", true},
+		{"/slack/events", true},
+		{"events", false},
+		{"/slack events", false},
+		{"/commands", false},
+		{"/interactions", false},
+		{"/metrics", false},
+		{"/version", false},
+		{"/oauth/callback", false},
+		{"/oauth/install", false},
+		{"/api/queues", false},
+		{"/api/queues/1", false},
+	}
+	for _, tt := range tests {
+		t.Setenv("SLACK_BOT_TOKEN", "xoxb-test")
+		t.Setenv("SLACK_SIGNING_SECRET", testSigningSecret)
+		t.Setenv("EVENTS_PATH", tt.value)
+
+		cfg, err := LoadConfig()
+		if (err == nil) != tt.ok {
+			t.Errorf("EVENTS_PATH=%q: err = %v, want ok %v", tt.value, err, tt.ok)
+			continue
+		}
+		if err == nil {
+			// Every accepted path must register without a conflict
+			NewServer(&SlackHandler{APIKey: "key", Teams: &TeamTokens{}}, "0", cfg.EventsPath).Handler()
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// digestTopReviewers is how many pending reviewers the digest names.
+const digestTopReviewers = 3
+
+// StartDigest schedules the digest on a standard five-field cron spec. An
+// empty spec disables it.
+func (sh *SlackHandler) StartDigest(spec string) {
+	if spec == "" {
+		log.Printf("[INFO] Digest disabled")
+		return
+	}
+
+	scheduler := cron.New()
+	if _, err := scheduler.AddFunc(spec, func() { sh.postDigests(time.Now()) }); err != nil {
+		log.Printf("[ERROR] Invalid DIGEST_CRON %q: %v", spec, err)
+		return
+	}
+	scheduler.Start()
+	log.Printf("[INFO] Posting digests on schedule %q", spec)
+}
+
+// postDigests posts a summary of open queues to every opted-in channel that
+// has any.
+func (sh *SlackHandler) postDigests(now time.Time) {
+	queues, err := sh.Store.List()
+	if err != nil {
+		log.Printf("[ERROR] Failed to list queues for digest: %v", err)
+		return
+	}
+
+	byChannel := make(map[string][]*Queue)
+	for _, queue := range queues {
+		if queue.Channel != "" && queue.isActive() && sh.Channels.Settings(queue.Channel).Digest {
+			byChannel[queue.Channel] = append(byChannel[queue.Channel], queue)
+		}
+	}
+
+	for channel, open := range byChannel {
+		th := sh.forTeam(open[0].TeamID)
+		text := th.Users.Unmention(renderDigest(open, now))
+		if _, _, err := th.API.PostMessage(channel, slack.MsgOptionText(text, false)); err != nil {
+			log.Printf("[ERROR] Failed to post digest to %s: %v", channel, err)
+		}
+	}
+}
+
+// renderDigest summarizes a channel's open queues, which must be ordered by
+// ID.
+func renderDigest(queues []*Queue, now time.Time) string {
+	var b strings.Builder
+	b.WriteString(t("digest.header", len(queues)))
+
+	oldest := queues[0]
+	for _, queue := range queues {
+		if queue.CreatedAt.Before(oldest.CreatedAt) {
+			oldest = queue
+		}
+	}
+	b.WriteString(t("digest.oldest", oldest.DisplayID(), oldest.Title, formatDuration(now.Sub(oldest.CreatedAt))))
+
+	var overdue []string
+	pending := make(map[string]int)
+	for _, queue := range queues {
+		if queue.isOverdue(now) {
+			overdue = append(overdue, queue.DisplayID())
+		}
+		for _, tag := range queue.pendingTags() {
+			pending[tag]++
+		}
+	}
+	if len(overdue) > 0 {
+		b.WriteString(t("digest.overdue", len(overdue), strings.Join(overdue, ", ")))
+	}
+
+	reviewers := make([]string, 0, len(pending))
+	for reviewer := range pending {
+		reviewers = append(reviewers, reviewer)
+	}
+	sort.Slice(reviewers, func(i, j int) bool {
+		if pending[reviewers[i]] != pending[reviewers[j]] {
+			return pending[reviewers[i]] > pending[reviewers[j]]
+		}
+		return reviewers[i] < reviewers[j]
+	})
+	if len(reviewers) > digestTopReviewers {
+		reviewers = reviewers[:digestTopReviewers]
+	}
+	if len(reviewers) > 0 {
+		names := make([]string, len(reviewers))
+		for i, reviewer := range reviewers {
+			names[i] = fmt.Sprintf("%s (%d)", reviewer, pending[reviewer])
+		}
+		b.WriteString(t("digest.top", strings.Join(names, ", ")))
+	}
+	return b.String()
+}
+
+func (sh *SlackHandler) handleQueueDigest(w http.ResponseWriter, ev *slackevents.MessageEvent) {
+	parts := strings.Fields(ev.Text)
+	if len(parts) != 3 || (parts[2] != "on" && parts[2] != "off") {
+		sh.replyError(ev, t("digest.usage"))
+		return
+	}
+
+	msg := t("digest.off")
+	if parts[2] == "on" {
+		msg = t("digest.on")
+	}
+	sh.setChannelConfig(ev, func() error { return sh.Channels.Set(ev.Channel, "digest", parts[2]) }, msg)
+}
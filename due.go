@@ -0,0 +1,55 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/olebedev/when"
+	"github.com/olebedev/when/rules/common"
+	"github.com/olebedev/when/rules/en"
+)
+
+// dueParser understands phrases such as "tomorrow 5pm", "in 2 days" or
+// "friday at 3pm".
+var dueParser = newDueParser()
+
+func newDueParser() *when.Parser {
+	w := when.New(nil)
+	w.Add(en.All...)
+	w.Add(common.All...)
+	return w
+}
+
+// dueLayouts are absolute formats accepted before falling back to natural
+// language.
+var dueLayouts = []string{"2006-01-02 15:04", "2006-01-02"}
+
+// parseDue resolves a due date relative to now, whose location is the
+// user's timezone. The whole phrase must be understood and lie in the future.
+func parseDue(text string, now time.Time) (time.Time, error) {
+	text = strings.TrimSpace(text)
+	for _, layout := range dueLayouts {
+		if due, err := time.ParseInLocation(layout, text, now.Location()); err == nil {
+			return checkDue(text, due, now)
+		}
+	}
+
+	result, err := dueParser.Parse(text, now)
+	if err != nil || result == nil || result.Index != 0 || len(result.Text) != len(text) {
+		return time.Time{}, errors.New(t("due.invalid", text))
+	}
+	return checkDue(text, result.Time, now)
+}
+
+func checkDue(text string, due, now time.Time) (time.Time, error) {
+	if !due.After(now) {
+		return time.Time{}, errors.New(t("due.past", text))
+	}
+	return due, nil
+}
+
+// formatTimestamp renders t in the viewer's timezone.
+func formatTimestamp(t time.Time, loc *time.Location) string {
+	return t.In(loc).Format("Mon Jan 2, 15:04 MST")
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// Action IDs for the App Home buttons.
+const (
+	actionApproveQueue = "approve_queue"
+	actionReviewQueue  = "review_queue"
+	actionRemoveQueue  = "remove_queue"
+)
+
+func (sh *SlackHandler) handleAppHomeOpened(ev *slackevents.AppHomeOpenedEvent) {
+	if ev.Tab != "home" {
+		return
+	}
+
+	sh.mu.Lock()
+	sh.homeViewers[ev.User] = true
+	sh.mu.Unlock()
+
+	sh.publishHome(ev.User)
+}
+
+// isAssignedTo reports whether the user is tagged on or reviewing the queue.
+func (q *Queue) isAssignedTo(userID string) bool {
+	if q.Reviewer == userID {
+		return true
+	}
+	for _, tag := range q.Tags {
+		if id, ok := parseMention(tag); ok && id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// refreshHomes republishes the App Home of the queue's owner, tags and
+// reviewer, if they have opened it before. Callers must hold sh.mu; the
+// views are published in the background once the lock is released.
+func (sh *SlackHandler) refreshHomes(queue *Queue) {
+	users := append([]string{queue.Owner, queue.Reviewer}, tagUsers(queue.Tags)...)
+	sh.refreshUserHomes(queue.TeamID, users...)
+}
+
+// refreshUserHomes republishes the App Home of the given users of the team,
+// if they have opened it before. Use it for users a change removed from a
+// queue, which refreshHomes no longer reaches. Callers must hold sh.mu.
+func (sh *SlackHandler) refreshUserHomes(teamID string, users ...string) {
+	for _, userID := range users {
+		if userID != "" && sh.homeViewers[userID] {
+			go sh.forTeam(teamID).publishHome(userID)
+		}
+	}
+}
+
+// tagUsers returns the user IDs of the mentions among tags.
+func tagUsers(tags []string) []string {
+	var users []string
+	for _, tag := range tags {
+		if id, ok := parseMention(tag); ok {
+			users = append(users, id)
+		}
+	}
+	return users
+}
+
+// handleRemoteUpdate refreshes the App Homes affected by a queue change made
+// on another instance.
+func (sh *SlackHandler) handleRemoteUpdate(queue *Queue) {
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	sh.refreshHomes(queue)
+}
+
+// publishHome renders the user's owned and assigned queues into their App
+// Home tab.
+func (sh *SlackHandler) publishHome(userID string) {
+	queues, err := sh.teamQueues()
+	if err != nil {
+		log.Printf("[ERROR] Failed to list queues for App Home of %s: %v", userID, err)
+		return
+	}
+
+	var owned, assigned []Queue
+	for _, queue := range queues {
+		switch {
+		case queue.isArchived():
+		case queue.Owner == userID:
+			owned = append(owned, *queue)
+		case queue.isAssignedTo(userID):
+			assigned = append(assigned, *queue)
+		}
+	}
+
+	loc := sh.Users.Location(userID)
+	blocks := []slack.Block{
+		slack.NewHeaderBlock(slack.NewTextBlockObject(slack.PlainTextType, t("home.assigned"), false, false)),
+	}
+	blocks = append(blocks, sh.homeQueueBlocks(assigned, false, loc)...)
+	blocks = append(blocks,
+		slack.NewDividerBlock(),
+		slack.NewHeaderBlock(slack.NewTextBlockObject(slack.PlainTextType, t("home.owned"), false, false)),
+	)
+	blocks = append(blocks, sh.homeQueueBlocks(owned, true, loc)...)
+
+	view := slack.HomeTabViewRequest{
+		Type:   slack.VTHomeTab,
+		Blocks: slack.Blocks{BlockSet: blocks},
+	}
+	if _, err := sh.API.PublishView(userID, view, ""); err != nil {
+		log.Printf("[ERROR] Failed to publish App Home for %s: %v", userID, err)
+	}
+}
+
+// homeQueueBlocks renders queues for the App Home. Assigned queues get
+// approve and review buttons; owned queues get a remove button.
+func (sh *SlackHandler) homeQueueBlocks(queues []Queue, owned bool, loc *time.Location) []slack.Block {
+	if len(queues) == 0 {
+		return []slack.Block{
+			slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, t("home.empty"), false, false), nil, nil),
+		}
+	}
+
+	var blocks []slack.Block
+	for _, queue := range queues {
+		status := queue.statusText()
+		if !queue.SLADeadline.IsZero() {
+			status += t("home.due", formatTimestamp(queue.SLADeadline, loc))
+		}
+		text := t("home.queue", queue.DisplayID(), queue.Title, queue.MRLink, queue.Owner, queue.approvalStatus(), status)
+		blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, sh.Users.Unmention(text), false, false), nil, nil))
+
+		id := queue.DisplayID()
+		if owned {
+			remove := slack.NewButtonBlockElement(actionRemoveQueue, id, slack.NewTextBlockObject(slack.PlainTextType, t("button.remove"), false, false)).WithStyle(slack.StyleDanger)
+			if sh.ConfirmRemove {
+				remove = remove.WithConfirm(slack.NewConfirmationBlockObject(
+					slack.NewTextBlockObject(slack.PlainTextType, t("home.remove_title"), false, false),
+					slack.NewTextBlockObject(slack.MarkdownType, sh.withPrefix(t("home.remove_confirm", id, queue.Title)), false, false),
+					slack.NewTextBlockObject(slack.PlainTextType, t("button.remove"), false, false),
+					slack.NewTextBlockObject(slack.PlainTextType, t("button.cancel"), false, false),
+				).WithStyle(slack.StyleDanger))
+			}
+			blocks = append(blocks, slack.NewActionBlock("queue_"+id, remove))
+			continue
+		}
+		blocks = append(blocks, slack.NewActionBlock("queue_"+id,
+			slack.NewButtonBlockElement(actionApproveQueue, id, slack.NewTextBlockObject(slack.PlainTextType, t("button.approve"), false, false)).WithStyle(slack.StylePrimary),
+			slack.NewButtonBlockElement(actionReviewQueue, id, slack.NewTextBlockObject(slack.PlainTextType, t("button.review"), false, false)),
+		))
+	}
+	return blocks
+}
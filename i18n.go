@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+)
+
+// Locales selectable via LOCALE.
+const (
+	LocaleEnglish    = "en"
+	LocaleIndonesian = "id"
+)
+
+// catalog holds the user-facing messages of each locale, keyed by message
+// key. English is complete; other locales fall back to it for missing keys.
+var catalog = map[string]map[string]string{
+	LocaleEnglish:    messagesEN,
+	LocaleIndonesian: messagesID,
+}
+
+// locale is the locale replies are written in. It is set once at startup by
+// SetLocale, before any handler runs.
+var locale = LocaleEnglish
+
+// SetLocale selects the locale for all replies. Keys the locale lacks are
+// logged, since they will be shown in English.
+func SetLocale(name string) error {
+	messages, ok := catalog[name]
+	if !ok {
+		return fmt.Errorf("invalid LOCALE %q: expected %s or %s", name, LocaleEnglish, LocaleIndonesian)
+	}
+	var missing []string
+	for key := range messagesEN {
+		if _, ok := messages[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		log.Printf("[WARN] Locale %s lacks %d messages, using English for: %v", name, len(missing), missing)
+	}
+	locale = name
+	return nil
+}
+
+// t returns the message for key in the current locale, formatted with args
+// like fmt.Sprintf.
+func t(key string, args ...any) string {
+	text, ok := catalog[locale][key]
+	if !ok {
+		text, ok = messagesEN[key]
+	}
+	if !ok {
+		log.Printf("[ERROR] Missing message %q", key)
+		return key
+	}
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
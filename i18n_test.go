@@ -0,0 +1,71 @@
+package main
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// formatVerb matches the fmt verbs in a message, ignoring escaped percents.
+var formatVerb = regexp.MustCompile(`%(\[\d+\])?[-+# 0]*\d*(\.\d+)?[a-zA-Z]`)
+
+// englishKeys returns every message key: the English catalog plus the
+// "help.<subcommand>" keys, whose English text lives in the command registry.
+func englishKeys() map[string]bool {
+	keys := make(map[string]bool, len(messagesEN))
+	for key := range messagesEN {
+		keys[key] = true
+	}
+	for _, cmd := range defaultCommands() {
+		keys["help."+strings.TrimPrefix(cmd.Name, defaultCommandPrefix+" ")] = true
+	}
+	return keys
+}
+
+func TestCatalogComplete(t *testing.T) {
+	keys := englishKeys()
+	for name, messages := range catalog {
+		if name == LocaleEnglish {
+			continue
+		}
+		var missing, extra []string
+		for key := range keys {
+			if _, ok := messages[key]; !ok {
+				missing = append(missing, key)
+			}
+		}
+		for key := range messages {
+			if !keys[key] {
+				extra = append(extra, key)
+			}
+		}
+		sort.Strings(missing)
+		sort.Strings(extra)
+		if len(missing) > 0 {
+			t.Errorf("locale %s lacks %v", name, missing)
+		}
+		if len(extra) > 0 {
+			t.Errorf("locale %s has keys English lacks: %v", name, extra)
+		}
+	}
+}
+
+func TestCatalogFormatVerbs(t *testing.T) {
+	for name, messages := range catalog {
+		for key, text := range messages {
+			en, ok := messagesEN[key]
+			if !ok {
+				continue
+			}
+			if got, want := len(formatVerb.FindAllString(stripEscapedPercent(text), -1)), len(formatVerb.FindAllString(stripEscapedPercent(en), -1)); got != want {
+				t.Errorf("locale %s, %s: %d format verbs, English has %d", name, key, got, want)
+			}
+		}
+	}
+}
+
+// stripEscapedPercent removes %% so it isn't mistaken for a verb.
+func stripEscapedPercent(text string) string {
+	return strings.ReplaceAll(text, "%%", "")
+}
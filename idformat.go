@@ -0,0 +1,307 @@
+package main
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/slack-go/slack"
+)
+
+// Queue ID formats selectable via ID_FORMAT.
+const (
+	// IDFormatNumeric shows the global integer ID, e.g. "42".
+	IDFormatNumeric = "numeric"
+	// IDFormatPrefixed shows a per-channel prefix and sequence, e.g. "ENG-7".
+	IDFormatPrefixed = "prefixed"
+	// IDFormatShort shows a short random code, e.g. "k3x9m".
+	IDFormatShort = "short"
+)
+
+const (
+	shortCodeAlphabet = "23456789abcdefghjkmnpqrstuvwxyz"
+	shortCodeLength   = 5
+)
+
+// keyPattern matches keys of the prefixed and short formats, which queues
+// keep after ID_FORMAT is switched back to numeric.
+var keyPattern = regexp.MustCompile(`^(?i:\S+-[0-9]+|[` + shortCodeAlphabet + `]{` + strconv.Itoa(shortCodeLength) + `})$`)
+
+// validIDFormat reports whether format is a supported ID_FORMAT value.
+func validIDFormat(format string) bool {
+	switch format {
+	case IDFormatNumeric, IDFormatPrefixed, IDFormatShort:
+		return true
+	}
+	return false
+}
+
+// DisplayID returns the ID users see and type for the queue.
+func (q *Queue) DisplayID() string {
+	if q.Key != "" {
+		return q.Key
+	}
+	return strconv.Itoa(q.ID)
+}
+
+// QueueIDs assigns the user-facing keys for queues in the non-numeric ID
+// formats.
+type QueueIDs struct {
+	Format string
+	// Prefixes maps channel IDs to explicit prefixes from ID_PREFIXES.
+	Prefixes map[string]string
+
+	api     SlackAPI
+	derived sync.Map // channel ID -> prefix derived from the channel name
+	// sequence is the last number handed out per prefix. Keys in the store
+	// raise it, so numbering carries on across restarts.
+	sequence map[string]int
+}
+
+// NewQueueIDs creates a new instance of QueueIDs.
+func NewQueueIDs(api SlackAPI, format string, prefixes map[string]string) *QueueIDs {
+	return &QueueIDs{
+		Format:   format,
+		Prefixes: prefixes,
+		api:      api,
+		sequence: make(map[string]int),
+	}
+}
+
+// prefix returns the channel's ID prefix, deriving it from the channel name
+// when it isn't configured. It calls the Slack API, so call it before taking
+// sh.mu.
+func (ids *QueueIDs) prefix(channel string) string {
+	if prefix, ok := ids.Prefixes[channel]; ok {
+		return prefix
+	}
+	if prefix, ok := ids.derived.Load(channel); ok {
+		return prefix.(string)
+	}
+
+	prefix := "Q"
+	info, err := ids.api.GetConversationInfo(&slack.GetConversationInfoInput{ChannelID: channel})
+	if err != nil {
+		log.Printf("[WARN] Failed to look up channel %s for its ID prefix: %v", channel, err)
+	} else if derived := derivePrefix(info.Name); derived != "" {
+		prefix = derived
+	}
+	ids.derived.Store(channel, prefix)
+	return prefix
+}
+
+// derivePrefix turns a channel name like "eng-backend" into "ENG".
+func derivePrefix(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if b.Len() == 3 {
+			break
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(unicode.ToUpper(r))
+		}
+	}
+	return b.String()
+}
+
+// nextKey returns a new key for a queue, or "" for the numeric format. taken
+// holds the lowercased keys already in use, as returned by takenKeys, and
+// gains the new key. Callers must hold sh.mu; prefix should come from a
+// prior call to ids.prefix.
+func (ids *QueueIDs) nextKey(prefix string, taken map[string]bool) string {
+	var key string
+	switch ids.Format {
+	case IDFormatPrefixed:
+		seq := ids.sequence[prefix]
+		for used := range taken {
+			if n, ok := keySequence(used, prefix); ok && n > seq {
+				seq = n
+			}
+		}
+		seq++
+		ids.sequence[prefix] = seq
+		key = fmt.Sprintf("%s-%d", prefix, seq)
+	case IDFormatShort:
+		for key == "" || taken[key] {
+			key = randomCode()
+		}
+	default:
+		return ""
+	}
+	taken[strings.ToLower(key)] = true
+	return key
+}
+
+// keySequence returns the number of a prefixed key such as "eng-7" if it
+// has the prefix.
+func keySequence(key, prefix string) (int, bool) {
+	rest, ok := strings.CutPrefix(key, strings.ToLower(prefix)+"-")
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.Atoi(rest)
+	return n, err == nil
+}
+
+// takenKeys returns the lowercased keys of the queues of every workspace.
+// Callers must hold sh.mu.
+func (sh *SlackHandler) takenKeys() (map[string]bool, error) {
+	queues, err := sh.Store.List()
+	if err != nil {
+		return nil, err
+	}
+	taken := make(map[string]bool, len(queues))
+	for _, queue := range queues {
+		if queue.Key != "" {
+			taken[strings.ToLower(queue.Key)] = true
+		}
+	}
+	return taken, nil
+}
+
+func randomCode() string {
+	code := make([]byte, shortCodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(shortCodeAlphabet))))
+		if err != nil {
+			panic(err)
+		}
+		code[i] = shortCodeAlphabet[n.Int64()]
+	}
+	return string(code)
+}
+
+// findQueue resolves a user-supplied queue ID, or an MR link, returning
+// ErrQueueNotFound if no queue of this workspace matches. Queues keep the ID
+// they were created with, so IDs of the other formats are accepted too.
+// Callers must hold sh.mu.
+func (sh *SlackHandler) findQueue(ref string) (*Queue, error) {
+	if link := sh.MRLinks.expand(ref); looksLikeURL(link) {
+		return sh.findQueueByLink(link)
+	}
+	if sh.IDs.Format == IDFormatNumeric {
+		queue, err := sh.findQueueByID(ref)
+		if !errors.Is(err, ErrQueueNotFound) {
+			return queue, err
+		}
+		return sh.findQueueByKey(ref)
+	}
+
+	queue, err := sh.findQueueByKey(ref)
+	if !errors.Is(err, ErrQueueNotFound) {
+		return queue, err
+	}
+	// Queues created while ID_FORMAT was numeric have no key
+	queue, err = sh.findQueueByID(ref)
+	if err == nil && queue.Key != "" {
+		return nil, ErrQueueNotFound
+	}
+	return queue, err
+}
+
+// findQueueByID returns the queue with the numeric ID. Callers must hold
+// sh.mu.
+func (sh *SlackHandler) findQueueByID(ref string) (*Queue, error) {
+	id, err := strconv.Atoi(ref)
+	if err != nil {
+		return nil, ErrQueueNotFound
+	}
+	queue, err := sh.Store.Get(id)
+	if err == nil && !sh.ownsTeam(queue.TeamID) {
+		return nil, ErrQueueNotFound
+	}
+	return queue, err
+}
+
+// findQueueByKey returns the queue with the key, ignoring case. Callers must
+// hold sh.mu.
+func (sh *SlackHandler) findQueueByKey(ref string) (*Queue, error) {
+	queues, err := sh.teamQueues()
+	if err != nil {
+		return nil, err
+	}
+	for _, queue := range queues {
+		if queue.Key != "" && strings.EqualFold(queue.Key, ref) {
+			return queue, nil
+		}
+	}
+	return nil, ErrQueueNotFound
+}
+
+// findQueueByLink returns the queue for an MR link. If the link was queued
+// more than once, unarchived queues win over archived ones and newer over
+// older. Callers must hold sh.mu.
+func (sh *SlackHandler) findQueueByLink(link string) (*Queue, error) {
+	queues, err := sh.teamQueues()
+	if err != nil {
+		return nil, err
+	}
+	link = normalizeMRLink(link)
+	var found *Queue
+	for _, queue := range queues {
+		if normalizeMRLink(queue.MRLink) != link {
+			continue
+		}
+		switch {
+		case found == nil:
+			found = queue
+		case found.isArchived() != queue.isArchived():
+			if !queue.isArchived() {
+				found = queue
+			}
+		case queue.CreatedAt.After(found.CreatedAt):
+			found = queue
+		}
+	}
+	if found == nil {
+		return nil, ErrQueueNotFound
+	}
+	return found, nil
+}
+
+// parseQueueIDs parses one or more queue IDs following the subcommand, e.g.
+// "queue remove 1 2 5". In the numeric format every token must be numeric.
+func (sh *SlackHandler) parseQueueIDs(command string) ([]string, error) {
+	parts := strings.Fields(command)
+	if len(parts) < 3 {
+		return nil, errors.New(t("queue.ids_usage", strings.Join(parts, " ")))
+	}
+
+	for _, part := range parts[2:] {
+		if !sh.validQueueID(part) {
+			return nil, errors.New(t("queue.invalid_id_part", part))
+		}
+	}
+	return parts[2:], nil
+}
+
+// parseQueueID parses the single queue ID following the subcommand.
+func (sh *SlackHandler) parseQueueID(command string) (string, error) {
+	parts := strings.Fields(command)
+	if len(parts) < 3 {
+		return "", errors.New(t("queue.id_usage"))
+	}
+
+	if !sh.validQueueID(parts[2]) {
+		return "", errors.New(t("queue.invalid_id"))
+	}
+	return parts[2], nil
+}
+
+func (sh *SlackHandler) validQueueID(ref string) bool {
+	if looksLikeURL(sh.MRLinks.expand(ref)) {
+		return true
+	}
+	if sh.IDs.Format == IDFormatNumeric {
+		_, err := strconv.Atoi(ref)
+		return err == nil || keyPattern.MatchString(ref)
+	}
+	return ref != ""
+}
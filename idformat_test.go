@@ -0,0 +1,72 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNextKey(t *testing.T) {
+	ids := NewQueueIDs(nil, IDFormatPrefixed, nil)
+	taken := map[string]bool{"eng-3": true, "eng-10": true, "ops-20": true, "engx-30": true}
+	for _, want := range []string{"ENG-11", "ENG-12"} {
+		if key := ids.nextKey("ENG", taken); key != want {
+			t.Errorf("nextKey = %q, want %q", key, want)
+		}
+	}
+	if !taken["eng-12"] {
+		t.Error("nextKey did not mark its key as taken")
+	}
+	if key := ids.nextKey("OPS", map[string]bool{}); key != "OPS-1" {
+		t.Errorf("nextKey for a new prefix = %q, want OPS-1", key)
+	}
+
+	ids = NewQueueIDs(nil, IDFormatShort, nil)
+	key := ids.nextKey("", taken)
+	if len(key) != shortCodeLength || !taken[key] {
+		t.Errorf("short nextKey = %q, want a new %d-character code", key, shortCodeLength)
+	}
+
+	if key := NewQueueIDs(nil, IDFormatNumeric, nil).nextKey("", taken); key != "" {
+		t.Errorf("numeric nextKey = %q, want none", key)
+	}
+}
+
+// TestIDFormatSwitch changes ID_FORMAT between commands, as a restart with a
+// new setting would, and checks that every queue can still be found.
+func TestIDFormatSwitch(t *testing.T) {
+	sh, api := newTestHandler(t, nil)
+	info := func(ref, want string) {
+		t.Helper()
+		api.reset()
+		command(sh, "U1", "queue info "+ref)
+		if got := api.last().Text; !strings.Contains(got, want) {
+			t.Errorf("info %s in %s format = %q, want it to contain %q", ref, sh.IDs.Format, got, want)
+		}
+	}
+
+	command(sh, "U1", `queue add "Numeric" https://example.com/mr/1 <@U2>`)
+
+	sh.IDs = NewQueueIDs(sh.API, IDFormatPrefixed, nil)
+	command(sh, "U1", `queue add "Prefixed" https://example.com/mr/2 <@U2>`)
+	if key := mustGet(t, sh, 2).Key; key != "GEN-1" {
+		t.Fatalf("key = %q, want GEN-1", key)
+	}
+	info("1", "Numeric")
+	info("gen-1", "Prefixed")
+	info("2", "Queue not found.")
+
+	// A restart forgets the sequence, which carries on from the store
+	sh.IDs = NewQueueIDs(sh.API, IDFormatPrefixed, nil)
+	command(sh, "U1", `queue add "After restart" https://example.com/mr/3 <@U2>`)
+	if key := mustGet(t, sh, 3).Key; key != "GEN-2" {
+		t.Errorf("key after restart = %q, want GEN-2", key)
+	}
+
+	sh.IDs = NewQueueIDs(sh.API, IDFormatNumeric, nil)
+	info("1", "Numeric")
+	info("GEN-1", "Prefixed")
+	command(sh, "U1", "queue remove GEN-1 GEN-2")
+	if queues, _ := sh.Store.List(); len(queues) != 1 {
+		t.Errorf("%d queues left after removing by key, want 1", len(queues))
+	}
+}
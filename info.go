@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+func (sh *SlackHandler) handleQueueInfo(w http.ResponseWriter, ev *slackevents.MessageEvent) {
+	ref, err := sh.parseQueueID(ev.Text)
+	if err != nil {
+		sh.replyError(ev, err.Error())
+		return
+	}
+
+	sh.mu.Lock()
+	queue, err := sh.findQueue(ref)
+	if err == nil {
+		queue = queue.clone()
+	}
+	sh.mu.Unlock()
+	if err != nil {
+		sh.replyQueueError(ev, err)
+		return
+	}
+
+	blocks := renderQueueInfo(queue, time.Now(), sh.Users.Location(ev.User), sh.Users.Unmention)
+	sh.reply(ev,
+		slack.MsgOptionText(t("info.fallback", queue.DisplayID(), queue.Title), false),
+		slack.MsgOptionBlocks(blocks...))
+}
+
+// renderQueueInfo renders every detail of a queue as Block Kit blocks.
+// unmention turns user mentions into plain names.
+func renderQueueInfo(queue *Queue, now time.Time, loc *time.Location, unmention func(string) string) []slack.Block {
+	markdown := func(text string) *slack.TextBlockObject {
+		return slack.NewTextBlockObject(slack.MarkdownType, unmention(text), false, false)
+	}
+	field := func(name, value string) *slack.TextBlockObject {
+		if value == "" {
+			value = "-"
+		}
+		return markdown(fmt.Sprintf("*%s*\n%s", name, value))
+	}
+
+	status := queue.statusText()
+
+	sla := ""
+	if queue.isOverdue(now) {
+		sla = t("info.overdue", formatDuration(now.Sub(queue.SLADeadline)))
+	} else if !queue.SLADeadline.IsZero() {
+		sla = formatTimestamp(queue.SLADeadline, loc)
+	}
+
+	approvals := strings.Join(queue.Approvers, ", ")
+	if queue.RequiredApprovals > 0 {
+		approvals = fmt.Sprintf("%d/%d %s", len(queue.Approvers), queue.RequiredApprovals, approvals)
+	}
+
+	inReview := ""
+	if d := queue.timeInReview(time.Now()); d > 0 {
+		inReview = formatDuration(d)
+	}
+
+	checklist := ""
+	if done, total := queue.checklistProgress(); total > 0 {
+		checklist = fmt.Sprintf("%d/%d", done, total)
+	}
+
+	created, updated := "", ""
+	if !queue.CreatedAt.IsZero() {
+		created = formatTimestamp(queue.CreatedAt, loc)
+	}
+	if !queue.UpdatedAt.IsZero() {
+		updated = formatTimestamp(queue.UpdatedAt, loc)
+	}
+
+	blocks := []slack.Block{
+		slack.NewHeaderBlock(slack.NewTextBlockObject(slack.PlainTextType,
+			fmt.Sprintf("%s. %s", queue.DisplayID(), queue.Title), false, false)),
+		slack.NewSectionBlock(markdown(queue.MRLink), nil, nil),
+	}
+	if queue.Description != "" {
+		blocks = append(blocks, slack.NewSectionBlock(markdown(formatDescription(queue.Description)), nil, nil))
+	}
+	blocks = append(blocks,
+		slack.NewSectionBlock(nil, []*slack.TextBlockObject{
+			field(t("info.owner"), fmt.Sprintf("<@%s>", queue.Owner)),
+			field(t("info.status"), status),
+			field(t("info.pending"), strings.Join(queue.Tags, ", ")),
+			field(t("info.approved_by"), approvals),
+			field(t("info.labels"), strings.Join(queue.Labels, ", ")),
+			field(t("info.size"), queue.Size),
+			field(t("info.checklist"), checklist),
+			field(t("info.review_time"), inReview),
+			field(t("info.due"), sla),
+			field(t("info.created"), created),
+			field(t("info.updated"), updated),
+		}, nil),
+	)
+	if len(queue.Checklist) > 0 {
+		blocks = append(blocks, slack.NewSectionBlock(markdown(renderChecklist(queue.Checklist)), nil, nil))
+	}
+	return blocks
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// HandleInteractionEndpoint handles Block Kit interactions such as the App
+// Home buttons, and the "Add to review queue" message shortcut and its modal.
+func (sh *SlackHandler) HandleInteractionEndpoint(w http.ResponseWriter, r *http.Request) {
+	body, ok := sh.readVerifiedBody(w, r)
+	if !ok {
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		log.Printf("[ERROR] Failed to parse interaction form: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	var callback slack.InteractionCallback
+	if err := json.Unmarshal([]byte(form.Get("payload")), &callback); err != nil {
+		log.Printf("[ERROR] Failed to unmarshal interaction payload: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	switch callback.Type {
+	case slack.InteractionTypeBlockActions:
+		// Buttons in App Home have no response URL
+		th := sh.forTeam(callback.Team.ID).withResponseURL(callback.ResponseURL)
+		sh.ackAndRun(w, func(w http.ResponseWriter) {
+			for _, action := range callback.ActionCallback.BlockActions {
+				th.handleBlockAction(w, callback.User.ID, action)
+			}
+		})
+	case slack.InteractionTypeMessageAction:
+		if callback.CallbackID != shortcutAddToQueue {
+			log.Printf("[WARN] Unsupported message shortcut: %s", callback.CallbackID)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		th := sh.forTeam(callback.Team.ID).withResponseURL(callback.ResponseURL)
+		// The trigger ID is valid for 3 seconds, so open the modal right after the ack
+		sh.ackAndRun(w, func(w http.ResponseWriter) {
+			th.handleAddShortcut(callback)
+		})
+	case slack.InteractionTypeViewSubmission:
+		if callback.View.CallbackID != viewAddQueue {
+			log.Printf("[WARN] Unsupported view submission: %s", callback.View.CallbackID)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		metadata := parseAddModalMetadata(callback.View.PrivateMetadata)
+		th := sh.forTeam(callback.Team.ID).withResponseURL(metadata.ResponseURL)
+		// Errors must be in the response itself to show in the modal
+		command, errs := th.addSubmission(callback.View, callback.User.ID)
+		if len(errs) > 0 {
+			writeJSON(w, http.StatusOK, slack.NewErrorsViewSubmissionResponse(errs))
+			return
+		}
+		sh.ackAndRun(w, func(w http.ResponseWriter) {
+			th.handleAddSubmission(w, callback.User.ID, command, metadata.Channel)
+		})
+	default:
+		log.Printf("[WARN] Unsupported interaction type: %s", callback.Type)
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// handleBlockAction runs the queue command behind a button as the clicking
+// user, replying in the queue's channel.
+func (sh *SlackHandler) handleBlockAction(w http.ResponseWriter, userID string, action *slack.BlockAction) {
+	var command string
+	switch action.ActionID {
+	case actionApproveQueue:
+		command = "approve"
+	case actionReviewQueue:
+		command = "review"
+	case actionTakeQueue:
+		command = "take"
+	case actionRemoveQueue:
+		// The button's confirm dialog, if enabled, has already been accepted
+		command = "remove --yes"
+	default:
+		log.Printf("[WARN] Unsupported block action: %s", action.ActionID)
+		return
+	}
+
+	ref := action.Value
+	sh.mu.Lock()
+	channel := userID
+	if queue, err := sh.findQueue(ref); err == nil && queue.Channel != "" {
+		channel = queue.Channel
+	}
+	sh.mu.Unlock()
+
+	sh.dispatchCommand(w, &slackevents.MessageEvent{
+		Type:    "message",
+		User:    userID,
+		Text:    fmt.Sprintf("%s %s %s", sh.CommandPrefix, command, ref),
+		Channel: channel,
+	})
+}
@@ -1,10 +1,11 @@
 package main
 
 import (
+	"context"
 	"log"
-	"os"
 
 	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 func main() {
@@ -14,10 +15,65 @@ func main() {
 		log.Fatal("[ERROR] Error loading .env file")
 	}
 
+	info := buildInfo()
+	log.Printf("[INFO] Starting version %s (commit %s, %s)", info.Version, info.Commit, info.GoVersion)
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		log.Fatalf("[ERROR] %v", err)
+	}
+	if err := SetLocale(cfg.Locale); err != nil {
+		log.Fatalf("[ERROR] %v", err)
+	}
+
+	store, err := OpenStore(context.Background(), cfg)
+	if err != nil {
+		log.Fatalf("[ERROR] Failed to open queue store: %v", err)
+	}
+
 	// Create SlackHandler and Server
-	slackHandler := NewSlackHandler(os.Getenv("SLACK_BOT_TOKEN"), os.Getenv("SLACK_SIGNING_SECRET"))
-	server := NewServer(slackHandler, "3000")
+	webhook := NewWebhookNotifier(cfg.WebhookURL)
+	reviewers, err := NewReviewerPool(cfg.ReviewerPoolPath)
+	if err != nil {
+		log.Fatalf("[ERROR] Failed to load reviewer pool: %v", err)
+	}
+	channels, err := NewChannelConfigs(cfg.ChannelsPath, cfg.ChannelDefaults())
+	if err != nil {
+		log.Fatalf("[ERROR] Failed to load channel config: %v", err)
+	}
+	// Without OAuth the bot serves only the workspace of SLACK_BOT_TOKEN
+	var teams *TeamTokens
+	if cfg.OAuth.ClientID != "" {
+		if teams, err = NewTeamTokens(cfg.OAuth.TokensPath); err != nil {
+			log.Fatalf("[ERROR] Failed to load team tokens: %v", err)
+		}
+	}
+	slackHandler, err := NewSlackHandler(cfg, NewSlackAPI(cfg.BotToken), store, reviewers, channels, teams, webhook)
+	if err != nil {
+		log.Fatalf("[ERROR] Failed to start Slack handler: %v", err)
+	}
+	slackHandler.RegisterMetrics(prometheus.DefaultRegisterer)
+	server := NewServer(slackHandler, "3000", cfg.EventsPath)
+
+	// Refresh views when another instance changes a queue, until shutdown
+	updatesCtx, stopUpdates := context.WithCancel(context.Background())
+	if rs, ok := store.(*RedisStore); ok {
+		go rs.Subscribe(updatesCtx, slackHandler.handleRemoteUpdate)
+	}
+
+	// Start the overdue reminder loop
+	slackHandler.StartReminders(cfg.Reminders)
+	slackHandler.StartSnapshots(cfg.Snapshot)
+	slackHandler.StartDigest(cfg.DigestCron)
+	slackHandler.StartAuthRefresh(cfg.AuthRefresh)
+	slackHandler.StartAutoArchive(cfg.AutoArchiveAfter)
 
 	// Start the server
 	server.Start()
+	stopUpdates()
+	slackHandler.DMs.Flush()
+
+	if err := slackHandler.SaveSnapshot(cfg.Snapshot); err != nil {
+		log.Printf("[ERROR] Failed to save queue snapshot on shutdown: %v", err)
+	}
 }
@@ -0,0 +1,264 @@
+package main
+
+// messagesEN is the English message catalog, and the fallback for keys
+// another locale lacks.
+var messagesEN = map[string]string{
+	"queue.invalid_id":          "Invalid queue ID.",
+	"queue.invalid_id_part":     "Invalid queue ID: %s",
+	"user.not_found":            "Couldn't find a Slack user for %s.",
+	"assign.usage":              "Usage: %s <id> @user",
+	"capacity.user_over":        "%s is over capacity (%d open queues).",
+	"capacity.each":             "Over capacity: each reviewer can have at most %d open queues.",
+	"capacity.over":             "Over capacity: %s. Each reviewer can have at most %d open queues.",
+	"capacity.load":             "%s (%d open)",
+	"capacity.you_over":         "You are over capacity (%d open queues).",
+	"assign.already":            "%s is already a reviewer on queue %s.",
+	"assign.added":              "%s added as a reviewer on queue %s.",
+	"assign.notice":             "<@%s> asked you to review *%s*: %s",
+	"queue.due_line":            "\nDue: %s",
+	"unassign.not_pending":      "%s is not a pending reviewer on this queue.",
+	"unassign.removed":          "%s removed as a reviewer from queue %s.",
+	"tags.usage":                "Usage: queue tags <id> set|add|remove @user [@user...]",
+	"tags.owner_only":           "Only <@%s> or an admin can edit the tags of this queue.",
+	"tags.need_one":             "A queue needs at least one pending reviewer; use `queue tags <id> set` to replace them.",
+	"tags.result":               "Tags on queue %s: %s",
+	"reassign.usage":            "Usage: queue reassign <id> @user [@user...]",
+	"reassign.owner_only":       "Only <@%s> or an admin can reassign this queue.",
+	"reassign.done":             "Queue %s reassigned to %s.",
+	"reassign.released":         "<@%s> reassigned *%s*; your review is no longer needed.",
+	"take.own":                  "You can't take your own queue.",
+	"review.already_yours":      "You are already reviewing this queue.",
+	"review.taken":              "Already being reviewed by <@%s>.",
+	"take.done":                 "<@%s> took queue %s and is now reviewing it.",
+	"queue.ids_usage":           "Usage: %s <id> [<id>...]",
+	"queue.id_usage":            "Usage: <command> <id>",
+	"size.invalid":              "Invalid size %q; use S, M, L, XL or a line count.",
+	"owner.owner_only":          "Only <@%s> or an admin can transfer this queue.",
+	"owner.already":             "<@%s> already owns this queue.",
+	"owner.done":                "Queue %s is now owned by %s.",
+	"owner.notice":              "<@%s> made you the owner of queue %s *%s*: %s",
+	"ping.none_pending":         "No reviewers are pending on this queue.",
+	"ping.throttled":            "Already pinged recently; try again in %s.",
+	"ping.message":              ":bell: Review reminder from <@%s>: queue %s *%s* is waiting on %s. %s",
+	"move.usage":                "Usage: queue move <id> #channel",
+	"move.channel_link":         "Mention the destination as a channel link, e.g. #team-reviews.",
+	"move.not_member":           "I'm not a member of <#%s>. Invite me with `/invite <@%s>` there first.",
+	"move.same_channel":         "Queue is already in <#%s>.",
+	"move.arrived":              "Queue %s moved here from <#%s> by <@%s>: *%s*\nMR Link: %s\n%s",
+	"move.left":                 "Queue %s moved to <#%s>.",
+	"reaction.changes":          ":%s: <@%s> requested changes on queue %s.",
+	"reaction.changes_notice":   "<@%s> requested changes on your queue %s *%s*: %s",
+	"reaction.approved":         ":%s: <@%s>: %s",
+	"reaction.withdrawn":        "<@%s> withdrew their approval of queue %s.",
+	"reaction.own_queue":        "You can't request changes on your own queue.",
+	"config.on_off":             "%s must be on or off.",
+	"config.approve_mode":       "approve_mode must be tag or count.",
+	"config.required_approvals": "required_approvals must be a positive number.",
+	"config.unknown":            "Unknown setting %q. Settings: %s.",
+	"config.save_failed":        "Couldn't save the channel setting. Please try again.",
+	"config.header":             "Settings for this channel:\n",
+	"config.source_default":     "default",
+	"config.source_channel":     "channel",
+	"config.admin_only":         "Only admins can change channel settings.",
+	"config.set":                "Set `%s` to %s for this channel.",
+	"config.unset":              "`%s` now uses the default for this channel.",
+	"config.usage":              "Usage: queue config | queue config set <key> <value> | queue config unset <key>",
+	"digest.header":             ":newspaper: *Review digest*: %d open queues\n",
+	"digest.oldest":             "• Oldest: %s *%s*, open for %s\n",
+	"digest.overdue":            "• Overdue: %d (%s)\n",
+	"digest.top":                "• Top pending reviewers: %s\n",
+	"digest.usage":              "Usage: queue digest on|off",
+	"digest.off":                "This channel will no longer receive the review digest.",
+	"digest.on":                 "This channel will now receive the review digest.",
+	"due.invalid":               "Couldn't understand the due date %q; try something like \"tomorrow 5pm\", \"in 2 days\" or \"YYYY-MM-DD HH:MM\".",
+	"due.past":                  "The due date %q is in the past.",
+	"home.assigned":             "Assigned to you",
+	"home.owned":                "Your queues",
+	"home.empty":                "_Nothing here._",
+	"home.due":                  " | Due: %s",
+	"home.queue":                "*%s. %s*\n%s\nOwner: <@%s> | %s\n%s",
+	"button.remove":             "Remove",
+	"button.cancel":             "Cancel",
+	"button.approve":            "Approve",
+	"button.review":             "Review",
+	"home.remove_title":         "Remove queue?",
+	"home.remove_confirm":       "Queue %s *%s* will be removed. You can restore it with `queue undo` for a few minutes.",
+	"stats.usage":               "Usage: queue stats [me] [window], e.g. `queue stats 30d` or `queue stats me`",
+	"stats.title":               "Queue stats for the last %s",
+	"stats.none":                "No queue activity in the last %s.",
+	"stats.created":             "*Created*\n%d",
+	"stats.approved":            "*Approved*\n%d",
+	"stats.removed":             "*Removed*\n%d",
+	"stats.no_approvals":        "No approvals yet.",
+	"stats.top":                 "*Top reviewers*\n",
+	"stats.first_review":        "*Avg time to first review*\n",
+	"stats.approval":            "*Avg time to approval*\n",
+	"stats.latency":             "%s (%d queues)",
+	"status.open":               "open",
+	"status.in_review":          "in review",
+	"status.approved":           "approved",
+	"status.closed":             "closed",
+	"status.inactive":           "Queue %s is %s; its reviewers can't change.",
+	"status.bad_transition":     "Queue %s is %s and can't become %s.",
+	"status.text_in_review_by":  "In review by <@%s>",
+	"status.text_in_review":     "In review",
+	"status.text_approved":      "Approved",
+	"status.text_closed":        "Closed",
+	"status.text_open":          "Waiting for review",
+	"close.owner_only":          "Only <@%s> or an admin can close this queue.",
+	"close.done":                "Queue %s has been closed.",
+	"undo.nothing":              "Nothing to undo.",
+	"undo.actor_only":           "Only <@%s> or an admin can undo this removal.",
+	"undo.restored_one":         "Restored queue %s.",
+	"undo.restored_many":        "Restored queues %s.",
+	"queue.not_found":           "Queue not found.",
+	"queue.store_failed":        "Couldn't reach the queue store. Please try again.",
+	"card.removed":              ":wastebasket: Queue %s *%s* was removed.",
+	"card.status":               "%s *Status:* %s\n%s",
+	"reminder.escalation":       ":rotating_light: Queue %s *%s* is overdue by %s with no response after %d reminders. Pending: %s. %s",
+	"info.overdue":              ":alarm_clock: overdue by %s",
+	"info.owner":                "Owner",
+	"info.status":               "Status",
+	"info.pending":              "Pending reviewers",
+	"info.approved_by":          "Approved by",
+	"info.labels":               "Labels",
+	"info.size":                 "Size",
+	"info.due":                  "Due",
+	"info.created":              "Created",
+	"info.updated":              "Updated",
+	"info.fallback":             "Queue %s: %s",
+	"help.header":               "Here are the available queue commands:\n",
+	"help.example":              "Example",
+	"help.aliases":              "Aliases",
+	"cmd.did_you_mean":          "Unknown command. Did you mean `%s`?",
+	"cmd.unknown":               "Unknown command. Try `queue help`.",
+	"add.title_first":           "The queue needs a title before the link, e.g. `queue add \"New Feature\" https://example.com @user1`.",
+	"add.usage":                 "Usage: queue add <title> <MR link> @tag @tag [--desc \"description\"] [--sla=4h | --due \"tomorrow 5pm\"] [--size=S|M|L|XL]",
+	"add.invalid_sla":           "Invalid SLA; use a duration such as 30m, 4h or 2d.",
+	"add.sla_or_due":            "Use either --sla or --due, not both.",
+	"add.unresolved":            "Couldn't find a Slack user for %s. Tag reviewers with a mention such as <@%s>.",
+	"add.all_over_capacity":     "Every tagged reviewer is over capacity: %s. Tag someone else.",
+	"add.need_reviewer":         "Tag at least one reviewer, e.g. `queue add \"New Feature\" https://example.com @user1`, or add people to the pool with `reviewers add @user`.",
+	"add.done":                  "Queue %s added: *%s*\nMR Link: %s\nTags: %s",
+	"add.size":                  "\nSize: %s",
+	"add.sla":                   "\nSLA: %s",
+	"add.skipped":               "\nSkipped (over capacity): %s",
+	"add.labels":                "\nLabels: %s",
+	"dryrun.pool":               "(next reviewer from the pool)",
+	"dryrun.header":             "Dry run, nothing was created. This would add:\nTitle: %s\nMR Link: %s\nTags: %s",
+	"list.usage":                "Usage: queue list [compact|full] [label <label>] [sort=id|age|priority] [--archived] [--json]",
+	"list.json_failed":          "Couldn't render the queues as JSON.",
+	"approval.none":             "none",
+	"approval.count":            "approved (%d/%d): %s | reviewers: %s",
+	"approval.tags":             "approved: %s | pending: %s",
+	"list.empty":                "No queues available.",
+	"list.created":              " | Created: %s",
+	"list.overdue":              " | :alarm_clock: overdue by %s",
+	"list.due":                  " | Due: %s",
+	"list.status":               "Status: %s | ",
+	"list.owner":                "Owner: <@%s>",
+	"list.reviewer":             " | Reviewer: <@%s>",
+	"list.labels":               " | Labels: %s",
+	"list.size":                 " | Size: %s",
+	"list.queue":                "%s ID: %s | Title: %s | MR: %s | %s%s%s\n",
+	"remove.confirm":            "Are you sure? Run `queue remove %s --yes` to confirm.",
+	"bulk.result":               "Queue %s: %s\n",
+	"remove.removed_one":        "Queue %s: removed\n",
+	"remove.done":               "Queue removed. Use `queue undo` to restore it.",
+	"clear.usage":               "Usage: queue clear [--all] [--yes]",
+	"clear.admin_only":          "Only admins can clear queues.",
+	"clear.empty":               "There are no queues to clear.",
+	"clear.confirm_channel":     "This will remove %d queues in this channel. Run `queue clear --yes` to confirm.",
+	"clear.confirm_all":         "This will remove %d queues across all channels. Run `queue clear --all --yes` to confirm.",
+	"clear.done":                "Removed %d queues. Use `queue undo` to restore them.",
+	"clear.failed":              " %d could not be removed; please try again.",
+	"bulk.not_found":            "not found",
+	"bulk.failed":               "failed, please try again",
+	"approve.owner_dm":          ":white_check_mark: Your queue %s *%s* is fully approved and ready to merge: %s",
+	"approve.owner_mention":     "<@%s> queue %s *%s* is fully approved and ready to merge.",
+	"approve.self":              "You can't approve your own MR.",
+	"approve.no_tags":           "Queue completed; no tags left.",
+	"approve.tag_not_found":     "Your tag was not found in the queue.",
+	"approve.tag_removed":       "Queue approved and tag removed.",
+	"approve.already":           "You have already approved this queue.",
+	"approve.enough":            "Queue already has its %d required approvals.",
+	"approve.counted":           "Queue approved (%d/%d).",
+	"approve.completed":         "Queue completed with %d approvals.",
+	"review.done":               "Queue %s is now in review.",
+	"update.reviewer_only":      "Only <@%s> or an admin can release this review.",
+	"update.done":               "Queue %s has been updated and is no longer in review.",
+	"desc.usage":                "Usage: queue desc <id> \"description\"",
+	"desc.cleared":              "Description cleared for queue %s.",
+	"desc.updated":              "Description updated for queue %s:\n%s",
+	"reviewers.usage":           "Usage: reviewers add|remove|list [@user]",
+	"reviewers.empty":           "The reviewer pool is empty.",
+	"reviewers.list":            "Reviewer pool: %s",
+	"reviewers.admin_only":      "Only admins can change the reviewer pool.",
+	"reviewers.usage_one":       "Usage: reviewers %s @user",
+	"reviewers.not_mention":     "%s is not a user mention.",
+	"reviewers.inactive":        "%s is not an active user.",
+	"reviewers.save_failed":     "Failed to save the reviewer pool.",
+	"reviewers.added":           "<@%s> added to the reviewer pool.",
+	"reviewers.already":         "<@%s> is already in the reviewer pool.",
+	"reviewers.removed":         "<@%s> removed from the reviewer pool.",
+	"reviewers.absent":          "<@%s> is not in the reviewer pool.",
+	"snooze.usage":              "Usage: queue snooze <id> <duration|off>, e.g. `queue snooze 3 2h`.",
+	"snooze.invalid_duration":   "Invalid duration; use one such as 30m, 4h or 2d, or off.",
+	"snooze.owner_only":         "Only <@%s> or an admin can snooze this queue.",
+	"snooze.not_snoozed":        "Reminders for queue %s aren't snoozed.",
+	"snooze.done":               "Reminders for queue %s are snoozed until %s.",
+	"snooze.resumed":            "Reminders for queue %s resumed.",
+	"list.snoozed":              " | :zzz: snoozed until %s",
+	"next.none":                 "Nothing to pick up: every open queue in this channel is claimed, yours or already approved by you.",
+	"next.pick":                 "Up next: queue %s *%s* from <@%s>, waiting %s.\nMR Link: %s",
+	"next.overdue":              "\n:alarm_clock: Overdue by %s",
+	"button.take":               "Take",
+	"slack.post_failed":         "Couldn't post to Slack, try again.",
+	"stats.me_none":             "You have no approvals in the last %s and no queues waiting on you.",
+	"stats.me_title":            "Your review stats for the last %s",
+	"stats.me_turnaround":       "*Avg turnaround*\n",
+	"stats.me_assigned":         "*Assigned now*\n%d",
+	"stats.me_overdue":          "*Overdue*\n%d",
+	"text.title_short":          "The title is too short; use at least %d characters so reviewers know what the change is.",
+	"text.description_short":    "The description is too short; use at least %d characters, or leave it out.",
+	"notify.batch":              "You have %d updates:\n\n",
+	"archive.owner_only":        "Only <@%s> or an admin can archive this queue.",
+	"archive.already":           "Queue %s is already archived.",
+	"archive.active":            "Queue %s is still %s; close it or get it approved before archiving.",
+	"archive.done":              "Queue %s archived. See it with `queue list --archived`.",
+	"list.unknown_sort":         "Unknown sort `%s`; use sort=id, sort=age or sort=priority. Showing the queues by ID.",
+	"whoami.user":               "Your user ID is `%s`; queues must tag you as `%s` for your approvals to count.",
+	"whoami.admin":              "\nYou are an admin.",
+	"whoami.tagged":             "\nTagged on active queues here: %s",
+	"whoami.untagged":           "\nYou aren't tagged on any active queue here.",
+	"cmd.channel_not_allowed":   "The review queue bot isn't enabled in this channel.",
+	"version.info":              "Version `%s`, commit `%s`, built with %s",
+	"checklist.usage":           "Usage: queue checklist <id> [add \"item\" | remove <n> | check <n> | uncheck <n>], e.g. `queue checklist 3 add \"docs updated\"`.",
+	"checklist.invalid_item":    "Invalid item number %q; use the number shown by `queue checklist <id>`.",
+	"checklist.no_item":         "Queue %[2]s has no checklist item %[1]d.",
+	"checklist.owner_only":      "Only <@%s> or an admin can add or remove checklist items.",
+	"checklist.reviewers_only":  "Only the queue's owner, its reviewers or an admin can check off items.",
+	"checklist.empty":           "Queue %s has no checklist. Add items with `queue checklist <id> add \"item\"`.",
+	"checklist.header":          "*Checklist for queue %s: %s* (%d/%d)\n",
+	"approve.checklist":         "This approval would complete the queue, but only %d of %d checklist items are checked. Check them off with `queue checklist <id> check <n>` first.",
+	"list.checklist":            " | Checklist: %d/%d",
+	"info.checklist":            "Checklist",
+	"add.title_quotes":          "Put a title with spaces in quotes, so it isn't mistaken for the link, e.g. `queue add \"New Feature\" https://example.com @user1`.",
+	"shortcut.modal_title":      "Add to review queue",
+	"shortcut.submit":           "Add",
+	"shortcut.cancel":           "Cancel",
+	"shortcut.title":            "Title",
+	"shortcut.link":             "MR link",
+	"shortcut.reviewers":        "Reviewers",
+	"shortcut.description":      "Description",
+	"shortcut.invalid_link":     "Enter a single http(s) link to the merge request.",
+	"shortcut.due":              "Due",
+	"shortcut.due_placeholder":  "e.g. tomorrow 5pm",
+	"slash.unknown":             "Unknown command %s. Use `/queue-add` to add a queue.",
+	"config.list_mode":          "list_mode must be full or compact.",
+	"list.compact_queue":        "`%s` %s\n",
+	"info.review_time":          "Time in review",
+	"stats.in_review":           "*Avg time in review*\n",
+	"shortcut.open_failed":      "Couldn't open the form in time. Please try again.",
+	"tags.all_approved":         "Everyone listed has already approved this queue; tag at least one reviewer who hasn't.",
+}
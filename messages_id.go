@@ -0,0 +1,293 @@
+package main
+
+// messagesID is the Indonesian message catalog.
+var messagesID = map[string]string{
+	"queue.invalid_id":          "ID antrean tidak valid.",
+	"queue.invalid_id_part":     "ID antrean tidak valid: %s",
+	"user.not_found":            "Tidak dapat menemukan pengguna Slack untuk %s.",
+	"assign.usage":              "Penggunaan: `%s <id> @user`",
+	"capacity.user_over":        "%s sudah melebihi kapasitas (%d antrean terbuka).",
+	"capacity.each":             "Melebihi kapasitas: setiap reviewer maksimal memiliki %d antrean terbuka.",
+	"capacity.over":             "Melebihi kapasitas: %s. Setiap reviewer maksimal memiliki %d antrean terbuka.",
+	"capacity.load":             "%s (%d terbuka)",
+	"capacity.you_over":         "Anda sudah melebihi kapasitas (%d antrean terbuka).",
+	"assign.already":            "%s sudah menjadi reviewer di antrean %s.",
+	"assign.added":              "%s ditambahkan sebagai reviewer di antrean %s.",
+	"assign.notice":             "<@%s> meminta Anda mereview *%s*: %s",
+	"queue.due_line":            "\nTenggat: %s",
+	"unassign.not_pending":      "%s bukan reviewer yang masih ditunggu di antrean ini.",
+	"unassign.removed":          "%s dihapus sebagai reviewer dari antrean %s.",
+	"tags.usage":                "Penggunaan: `queue tags <id> set|add|remove @user [@user...]`",
+	"tags.owner_only":           "Hanya <@%s> atau admin yang dapat mengubah tag antrean ini.",
+	"tags.need_one":             "Antrean membutuhkan setidaknya satu reviewer; gunakan `queue tags <id> set` untuk menggantinya.",
+	"tags.result":               "Tag pada antrean %s: %s",
+	"reassign.usage":            "Penggunaan: `queue reassign <id> @user [@user...]`",
+	"reassign.owner_only":       "Hanya <@%s> atau admin yang dapat mengganti reviewer antrean ini.",
+	"reassign.done":             "Reviewer antrean %s diganti menjadi %s.",
+	"reassign.released":         "<@%s> mengganti reviewer *%s*; review Anda tidak lagi diperlukan.",
+	"take.own":                  "Anda tidak dapat mengambil antrean Anda sendiri.",
+	"review.already_yours":      "Anda sudah mereview antrean ini.",
+	"review.taken":              "Sedang direview oleh <@%s>.",
+	"take.done":                 "<@%s> mengambil antrean %s dan sedang mereviewnya.",
+	"queue.ids_usage":           "Penggunaan: `%s <id> [<id>...]`",
+	"queue.id_usage":            "Penggunaan: <perintah> <id>",
+	"size.invalid":              "Ukuran %q tidak valid; gunakan S, M, L, XL atau jumlah baris.",
+	"owner.owner_only":          "Hanya <@%s> atau admin yang dapat memindahkan kepemilikan antrean ini.",
+	"owner.already":             "<@%s> sudah menjadi pemilik antrean ini.",
+	"owner.done":                "Antrean %s kini dimiliki oleh %s.",
+	"owner.notice":              "<@%s> menjadikan Anda pemilik antrean %s *%s*: %s",
+	"ping.none_pending":         "Tidak ada reviewer yang masih ditunggu di antrean ini.",
+	"ping.throttled":            "Sudah diingatkan baru-baru ini; coba lagi dalam %s.",
+	"ping.message":              ":bell: Pengingat review dari <@%s>: antrean %s *%s* menunggu %s. %s",
+	"move.usage":                "Penggunaan: `queue move <id> #channel`",
+	"move.channel_link":         "Sebutkan tujuan sebagai tautan channel, misalnya #team-reviews.",
+	"move.not_member":           "Saya bukan anggota <#%s>. Undang saya dengan `/invite <@%s>` di sana terlebih dahulu.",
+	"move.same_channel":         "Antrean sudah berada di <#%s>.",
+	"move.arrived":              "Antrean %s dipindahkan ke sini dari <#%s> oleh <@%s>: *%s*\nLink MR: %s\n%s",
+	"move.left":                 "Antrean %s dipindahkan ke <#%s>.",
+	"reaction.changes":          ":%s: <@%s> meminta perubahan pada antrean %s.",
+	"reaction.changes_notice":   "<@%s> meminta perubahan pada antrean Anda %s *%s*: %s",
+	"reaction.approved":         ":%s: <@%s>: %s",
+	"reaction.withdrawn":        "<@%s> menarik persetujuannya atas antrean %s.",
+	"reaction.own_queue":        "Anda tidak dapat meminta perubahan pada antrean Anda sendiri.",
+	"config.on_off":             "%s harus on atau off.",
+	"config.approve_mode":       "approve_mode harus tag atau count.",
+	"config.required_approvals": "required_approvals harus berupa angka positif.",
+	"config.unknown":            "Pengaturan %q tidak dikenal. Pengaturan: %s.",
+	"config.save_failed":        "Tidak dapat menyimpan pengaturan channel. Silakan coba lagi.",
+	"config.header":             "Pengaturan untuk channel ini:\n",
+	"config.source_default":     "bawaan",
+	"config.source_channel":     "channel",
+	"config.admin_only":         "Hanya admin yang dapat mengubah pengaturan channel.",
+	"config.set":                "`%s` diatur ke %s untuk channel ini.",
+	"config.unset":              "`%s` kini memakai nilai bawaan untuk channel ini.",
+	"config.usage":              "Penggunaan: `queue config` | `queue config set <key> <value>` | `queue config unset <key>`",
+	"digest.header":             ":newspaper: *Ringkasan review*: %d antrean terbuka\n",
+	"digest.oldest":             "• Terlama: %s *%s*, terbuka selama %s\n",
+	"digest.overdue":            "• Lewat tenggat: %d (%s)\n",
+	"digest.top":                "• Reviewer dengan antrean terbanyak: %s\n",
+	"digest.usage":              "Penggunaan: `queue digest on|off`",
+	"digest.off":                "Channel ini tidak akan lagi menerima ringkasan review.",
+	"digest.on":                 "Channel ini kini akan menerima ringkasan review.",
+	"due.invalid":               "Tidak dapat memahami tenggat %q; coba misalnya \"tomorrow 5pm\", \"in 2 days\" atau \"YYYY-MM-DD HH:MM\".",
+	"due.past":                  "Tenggat %q sudah lewat.",
+	"home.assigned":             "Ditugaskan kepada Anda",
+	"home.owned":                "Antrean Anda",
+	"home.empty":                "_Belum ada apa-apa._",
+	"home.due":                  " | Tenggat: %s",
+	"home.queue":                "*%s. %s*\n%s\nPemilik: <@%s> | %s\n%s",
+	"button.remove":             "Hapus",
+	"button.cancel":             "Batal",
+	"button.approve":            "Setujui",
+	"button.review":             "Review",
+	"home.remove_title":         "Hapus antrean?",
+	"home.remove_confirm":       "Antrean %s *%s* akan dihapus. Anda dapat memulihkannya dengan `queue undo` selama beberapa menit.",
+	"stats.usage":               "Penggunaan: `queue stats [me] [rentang]`, misalnya `queue stats 30d` atau `queue stats me`",
+	"stats.title":               "Statistik antrean selama %s terakhir",
+	"stats.none":                "Tidak ada aktivitas antrean selama %s terakhir.",
+	"stats.created":             "*Dibuat*\n%d",
+	"stats.approved":            "*Disetujui*\n%d",
+	"stats.removed":             "*Dihapus*\n%d",
+	"stats.no_approvals":        "Belum ada persetujuan.",
+	"stats.top":                 "*Reviewer teratas*\n",
+	"stats.first_review":        "*Rata-rata waktu hingga review pertama*\n",
+	"stats.approval":            "*Rata-rata waktu hingga disetujui*\n",
+	"stats.latency":             "%s (%d antrean)",
+	"status.open":               "terbuka",
+	"status.in_review":          "sedang direview",
+	"status.approved":           "disetujui",
+	"status.closed":             "ditutup",
+	"status.inactive":           "Antrean %s %s; reviewernya tidak dapat diubah.",
+	"status.bad_transition":     "Antrean %s %s dan tidak dapat menjadi %s.",
+	"status.text_in_review_by":  "Sedang direview oleh <@%s>",
+	"status.text_in_review":     "Sedang direview",
+	"status.text_approved":      "Disetujui",
+	"status.text_closed":        "Ditutup",
+	"status.text_open":          "Menunggu review",
+	"close.owner_only":          "Hanya <@%s> atau admin yang dapat menutup antrean ini.",
+	"close.done":                "Antrean %s telah ditutup.",
+	"undo.nothing":              "Tidak ada yang bisa dibatalkan.",
+	"undo.actor_only":           "Hanya <@%s> atau admin yang dapat membatalkan penghapusan ini.",
+	"undo.restored_one":         "Antrean %s dipulihkan.",
+	"undo.restored_many":        "Antrean %s dipulihkan.",
+	"queue.not_found":           "Antrean tidak ditemukan.",
+	"queue.store_failed":        "Tidak dapat menghubungi penyimpanan antrean. Silakan coba lagi.",
+	"card.removed":              ":wastebasket: Antrean %s *%s* telah dihapus.",
+	"card.status":               "%s *Status:* %s\n%s",
+	"reminder.escalation":       ":rotating_light: Antrean %s *%s* sudah lewat tenggat %s tanpa tanggapan setelah %d pengingat. Menunggu: %s. %s",
+	"info.overdue":              ":alarm_clock: lewat tenggat %s",
+	"info.owner":                "Pemilik",
+	"info.status":               "Status",
+	"info.pending":              "Reviewer yang ditunggu",
+	"info.approved_by":          "Disetujui oleh",
+	"info.labels":               "Label",
+	"info.size":                 "Ukuran",
+	"info.due":                  "Tenggat",
+	"info.created":              "Dibuat",
+	"info.updated":              "Diperbarui",
+	"info.fallback":             "Antrean %s: %s",
+	"help.header":               "Berikut perintah queue yang tersedia:\n",
+	"help.example":              "Contoh",
+	"help.aliases":              "Alias",
+	"cmd.did_you_mean":          "Perintah tidak dikenal. Maksud Anda `%s`?",
+	"cmd.unknown":               "Perintah tidak dikenal. Coba `queue help`.",
+	"add.title_first":           "Antrean membutuhkan judul sebelum link, misalnya `queue add \"Fitur Baru\" https://example.com @user1`.",
+	"add.usage":                 "Penggunaan: `queue add <judul> <link MR> @tag @tag [--desc \"deskripsi\"] [--sla=4h | --due \"tomorrow 5pm\"] [--size=S|M|L|XL]`",
+	"add.invalid_sla":           "SLA tidak valid; gunakan durasi seperti 30m, 4h atau 2d.",
+	"add.sla_or_due":            "Gunakan --sla atau --due, jangan keduanya.",
+	"add.unresolved":            "Tidak dapat menemukan pengguna Slack untuk %s. Tandai reviewer dengan mention seperti <@%s>.",
+	"add.all_over_capacity":     "Semua reviewer yang ditandai sudah melebihi kapasitas: %s. Tandai orang lain.",
+	"add.need_reviewer":         "Tandai minimal satu reviewer, misalnya `queue add \"Fitur Baru\" https://example.com @user1`, atau tambahkan orang ke pool dengan `reviewers add @user`.",
+	"add.done":                  "Antrean %s ditambahkan: *%s*\nLink MR: %s\nTag: %s",
+	"add.size":                  "\nUkuran: %s",
+	"add.sla":                   "\nSLA: %s",
+	"add.skipped":               "\nDilewati (melebihi kapasitas): %s",
+	"add.labels":                "\nLabel: %s",
+	"dryrun.pool":               "(reviewer berikutnya dari pool)",
+	"dryrun.header":             "Uji coba, tidak ada yang dibuat. Yang akan ditambahkan:\nJudul: %s\nLink MR: %s\nTag: %s",
+	"list.usage":                "Penggunaan: `queue list [compact|full] [label <label>] [sort=id|age|priority] [--archived] [--json]`",
+	"list.json_failed":          "Tidak dapat menampilkan antrean sebagai JSON.",
+	"approval.none":             "tidak ada",
+	"approval.count":            "disetujui (%d/%d): %s | reviewer: %s",
+	"approval.tags":             "disetujui: %s | menunggu: %s",
+	"list.empty":                "Tidak ada antrean.",
+	"list.created":              " | Dibuat: %s",
+	"list.overdue":              " | :alarm_clock: lewat tenggat %s",
+	"list.due":                  " | Tenggat: %s",
+	"list.status":               "Status: %s | ",
+	"list.owner":                "Pemilik: <@%s>",
+	"list.reviewer":             " | Reviewer: <@%s>",
+	"list.labels":               " | Label: %s",
+	"list.size":                 " | Ukuran: %s",
+	"list.queue":                "%s ID: %s | Judul: %s | MR: %s | %s%s%s\n",
+	"remove.confirm":            "Anda yakin? Jalankan `queue remove %s --yes` untuk mengonfirmasi.",
+	"bulk.result":               "Antrean %s: %s\n",
+	"remove.removed_one":        "Antrean %s: dihapus\n",
+	"remove.done":               "Antrean dihapus. Gunakan `queue undo` untuk memulihkannya.",
+	"clear.usage":               "Penggunaan: `queue clear [--all] [--yes]`",
+	"clear.admin_only":          "Hanya admin yang dapat mengosongkan antrean.",
+	"clear.empty":               "Tidak ada antrean untuk dikosongkan.",
+	"clear.confirm_channel":     "Ini akan menghapus %d antrean di channel ini. Jalankan `queue clear --yes` untuk mengonfirmasi.",
+	"clear.confirm_all":         "Ini akan menghapus %d antrean di semua channel. Jalankan `queue clear --all --yes` untuk mengonfirmasi.",
+	"clear.done":                "%d antrean dihapus. Gunakan `queue undo` untuk memulihkannya.",
+	"clear.failed":              " %d tidak dapat dihapus; silakan coba lagi.",
+	"bulk.not_found":            "tidak ditemukan",
+	"bulk.failed":               "gagal, silakan coba lagi",
+	"approve.owner_dm":          ":white_check_mark: Antrean Anda %s *%s* sudah disetujui sepenuhnya dan siap di-merge: %s",
+	"approve.owner_mention":     "<@%s> antrean %s *%s* sudah disetujui sepenuhnya dan siap di-merge.",
+	"approve.self":              "Anda tidak dapat menyetujui MR Anda sendiri.",
+	"approve.no_tags":           "Antrean selesai; tidak ada tag tersisa.",
+	"approve.tag_not_found":     "Tag Anda tidak ditemukan di antrean.",
+	"approve.tag_removed":       "Antrean disetujui dan tag dihapus.",
+	"approve.already":           "Anda sudah menyetujui antrean ini.",
+	"approve.enough":            "Antrean sudah memiliki %d persetujuan yang dibutuhkan.",
+	"approve.counted":           "Antrean disetujui (%d/%d).",
+	"approve.completed":         "Antrean selesai dengan %d persetujuan.",
+	"review.done":               "Antrean %s kini sedang direview.",
+	"update.reviewer_only":      "Hanya <@%s> atau admin yang dapat melepas review ini.",
+	"update.done":               "Antrean %s telah diperbarui dan tidak lagi direview.",
+	"desc.usage":                "Penggunaan: `queue desc <id> \"deskripsi\"`",
+	"desc.cleared":              "Deskripsi antrean %s dihapus.",
+	"desc.updated":              "Deskripsi antrean %s diperbarui:\n%s",
+	"reviewers.usage":           "Penggunaan: `reviewers add|remove|list [@user]`",
+	"reviewers.empty":           "Pool reviewer kosong.",
+	"reviewers.list":            "Pool reviewer: %s",
+	"reviewers.admin_only":      "Hanya admin yang dapat mengubah pool reviewer.",
+	"reviewers.usage_one":       "Penggunaan: `reviewers %s @user`",
+	"reviewers.not_mention":     "%s bukan mention pengguna.",
+	"reviewers.inactive":        "%s bukan pengguna aktif.",
+	"reviewers.save_failed":     "Gagal menyimpan pool reviewer.",
+	"reviewers.added":           "<@%s> ditambahkan ke pool reviewer.",
+	"reviewers.already":         "<@%s> sudah ada di pool reviewer.",
+	"reviewers.removed":         "<@%s> dihapus dari pool reviewer.",
+	"reviewers.absent":          "<@%s> tidak ada di pool reviewer.",
+	"snooze.usage":              "Penggunaan: `queue snooze <id> <durasi|off>`, misalnya `queue snooze 3 2h`.",
+	"snooze.invalid_duration":   "Durasi tidak valid; gunakan seperti 30m, 4h atau 2d, atau off.",
+	"snooze.owner_only":         "Hanya <@%s> atau admin yang dapat menunda pengingat antrean ini.",
+	"snooze.not_snoozed":        "Pengingat antrean %s tidak sedang ditunda.",
+	"snooze.done":               "Pengingat antrean %s ditunda hingga %s.",
+	"snooze.resumed":            "Pengingat antrean %s dilanjutkan.",
+	"list.snoozed":              " | :zzz: ditunda hingga %s",
+	"next.none":                 "Tidak ada yang bisa diambil: semua antrean terbuka di channel ini sudah diklaim, milik Anda, atau sudah Anda setujui.",
+	"next.pick":                 "Berikutnya: antrean %s *%s* dari <@%s>, menunggu %s.\nLink MR: %s",
+	"next.overdue":              "\n:alarm_clock: Lewat tenggat %s",
+	"button.take":               "Ambil",
+	"slack.post_failed":         "Tidak dapat mengirim ke Slack, silakan coba lagi.",
+	"stats.me_none":             "Anda tidak memiliki persetujuan dalam %s terakhir dan tidak ada antrean yang menunggu Anda.",
+	"stats.me_title":            "Statistik review Anda untuk %s terakhir",
+	"stats.me_turnaround":       "*Rata-rata waktu penyelesaian*\n",
+	"stats.me_assigned":         "*Ditugaskan saat ini*\n%d",
+	"stats.me_overdue":          "*Lewat tenggat*\n%d",
+	"text.title_short":          "Judul terlalu pendek; gunakan minimal %d karakter agar reviewer tahu perubahan apa ini.",
+	"text.description_short":    "Deskripsi terlalu pendek; gunakan minimal %d karakter, atau kosongkan.",
+	"notify.batch":              "Anda memiliki %d pembaruan:\n\n",
+	"archive.owner_only":        "Hanya <@%s> atau admin yang dapat mengarsipkan antrean ini.",
+	"archive.already":           "Antrean %s sudah diarsipkan.",
+	"archive.active":            "Antrean %s masih %s; tutup atau selesaikan persetujuannya sebelum diarsipkan.",
+	"archive.done":              "Antrean %s diarsipkan. Lihat dengan `queue list --archived`.",
+	"list.unknown_sort":         "Urutan `%s` tidak dikenal; gunakan sort=id, sort=age atau sort=priority. Menampilkan antrean berdasarkan ID.",
+	"whoami.user":               "ID pengguna Anda adalah `%s`; antrean harus menandai Anda sebagai `%s` agar persetujuan Anda dihitung.",
+	"whoami.admin":              "\nAnda adalah admin.",
+	"whoami.tagged":             "\nDitandai pada antrean aktif di sini: %s",
+	"whoami.untagged":           "\nAnda tidak ditandai pada antrean aktif mana pun di sini.",
+	"cmd.channel_not_allowed":   "Bot antrean review tidak diaktifkan di kanal ini.",
+	"version.info":              "Versi `%s`, commit `%s`, dibangun dengan %s",
+	"checklist.usage":           "Penggunaan: queue checklist <id> [add \"item\" | remove <n> | check <n> | uncheck <n>], mis. `queue checklist 3 add \"dokumentasi diperbarui\"`.",
+	"checklist.invalid_item":    "Nomor item %q tidak valid; gunakan nomor yang ditampilkan oleh `queue checklist <id>`.",
+	"checklist.no_item":         "Antrean %[2]s tidak memiliki item checklist %[1]d.",
+	"checklist.owner_only":      "Hanya <@%s> atau admin yang dapat menambah atau menghapus item checklist.",
+	"checklist.reviewers_only":  "Hanya pemilik antrean, reviewernya, atau admin yang dapat mencentang item.",
+	"checklist.empty":           "Antrean %s tidak memiliki checklist. Tambahkan item dengan `queue checklist <id> add \"item\"`.",
+	"checklist.header":          "*Checklist antrean %s: %s* (%d/%d)\n",
+	"approve.checklist":         "Persetujuan ini akan menyelesaikan antrean, tetapi baru %d dari %d item checklist yang dicentang. Centang terlebih dahulu dengan `queue checklist <id> check <n>`.",
+	"list.checklist":            " | Checklist: %d/%d",
+	"info.checklist":            "Checklist",
+	"add.title_quotes":          "Beri tanda kutip pada judul yang mengandung spasi agar tidak dikira link, misalnya `queue add \"Fitur Baru\" https://example.com @user1`.",
+	"shortcut.modal_title":      "Tambah ke antrean",
+	"shortcut.submit":           "Tambah",
+	"shortcut.cancel":           "Batal",
+	"shortcut.title":            "Judul",
+	"shortcut.link":             "Link MR",
+	"shortcut.reviewers":        "Reviewer",
+	"shortcut.description":      "Deskripsi",
+	"shortcut.invalid_link":     "Masukkan satu link http(s) ke merge request.",
+	"shortcut.due":              "Tenggat",
+	"shortcut.due_placeholder":  "mis. tomorrow 5pm",
+	"slash.unknown":             "Perintah %s tidak dikenal. Gunakan `/queue-add` untuk menambah antrean.",
+	"config.list_mode":          "list_mode harus full atau compact.",
+	"list.compact_queue":        "`%s` %s\n",
+	"info.review_time":          "Waktu dalam review",
+	"stats.in_review":           "*Rata-rata waktu dalam review*\n",
+	"shortcut.open_failed":      "Formulir tidak dapat dibuka tepat waktu. Silakan coba lagi.",
+	"tags.all_approved":         "Semua yang disebutkan sudah menyetujui antrean ini; tag setidaknya satu reviewer yang belum.",
+	"help.add":                  "Menambahkan antrean dengan judul, link, tag reviewer (mention pengguna), label opsional (kata lain apa pun, misalnya hotfix), deskripsi opsional, SLA review atau tenggat opsional (dalam zona waktu Anda), dan ukuran perubahan opsional (S, M, L, XL atau jumlah baris) agar review kecil mudah terlihat. Link boleh berupa !123 atau #123 jika GITLAB_MR_BASE_URL atau GITHUB_PR_BASE_URL diatur. Tanpa tag, reviewer dipilih dari pool reviewer; jika pool kosong, minimal satu tag wajib diisi. --dry-run hanya menampilkan apa yang akan ditambahkan",
+	"help.list":                 "Menampilkan antrean di channel ini, opsional hanya yang memiliki label tertentu, diurutkan berdasarkan ID (bawaan), usia (terlama dulu) atau prioritas (lewat tenggat, lalu tenggat terdekat, lalu terlama). compact hanya menampilkan ID dan judul, full menampilkan detailnya; pengaturan list_mode channel menentukan bawaannya. --archived menampilkan antrean yang diarsipkan. --json mengirimkannya sebagai array JSON untuk skrip",
+	"help.info":                 "Menampilkan semua detail antrean: link, deskripsi, pemilik, reviewer dan persetujuan, label, tenggat, dan klaim review",
+	"help.remove":               "Menghapus satu atau beberapa antrean berdasarkan ID atau tautan MR. Jika konfirmasi penghapusan aktif, tambahkan --yes untuk mengonfirmasi",
+	"help.clear":                "Menghapus semua antrean di channel ini, atau di semua channel dengan --all (khusus admin). Tanpa --yes hanya menyebutkan berapa yang akan dihapus",
+	"help.undo":                 "Memulihkan antrean yang dihapus oleh `queue remove` terakhir di channel ini (dalam 5 menit; khusus penghapus atau admin)",
+	"help.approve":              "Menyetujui satu atau beberapa antrean berdasarkan ID atau tautan MR. Dalam mode tag (bawaan) tag Anda dihapus dan antrean selesai saat tidak ada tag tersisa; dalam mode count siapa pun boleh menyetujui sekali dan antrean selesai saat jumlah persetujuan terpenuhi. Pemilik tidak dapat menyetujui antreannya sendiri kecuali ALLOW_SELF_APPROVE diatur. Memberi reaksi :white_check_mark: pada pesan antrean juga menyetujuinya (menghapus reaksi menarik persetujuan), dan :x: meminta perubahan",
+	"help.review":               "Mengklaim antrean untuk direview. Antrean dapat disebut dengan tautan MR-nya alih-alih ID",
+	"help.take":                 "Menandai Anda pada antrean dan mengklaimnya untuk direview sekaligus",
+	"help.update":               "Melepas klaim review pada antrean dan mengembalikannya ke terbuka (khusus reviewer atau admin)",
+	"help.close":                "Menutup antrean tanpa menghapusnya (khusus pemilik atau admin)",
+	"help.ping":                 "Mengingatkan reviewer yang ditunggu pada antrean sekarang juga (maksimal sekali setiap 10 menit per antrean)",
+	"help.assign":               "Menambahkan reviewer ke antrean yang sudah ada dan memberi tahu mereka",
+	"help.unassign":             "Menghapus reviewer yang ditunggu dari antrean",
+	"help.tags":                 "Mengganti, menambah, atau mengurangi reviewer antrean (khusus pemilik atau admin)",
+	"help.reassign":             "Mengganti semua reviewer antrean dan menghapus persetujuannya, sambil memberi tahu reviewer yang dihapus dan ditambahkan (khusus pemilik atau admin)",
+	"help.owner":                "Memindahkan kepemilikan antrean ke pemilik baru dan memberi tahu mereka (khusus pemilik atau admin)",
+	"help.move":                 "Memindahkan antrean ke channel lain tempat bot menjadi anggota",
+	"help.desc":                 "Mengatur atau menghapus deskripsi antrean",
+	"help.stats":                "Menampilkan jumlah antrean dibuat/disetujui/dihapus, waktu review, dan reviewer teratas (rentang bawaan 7d). Dengan me, hanya menampilkan kepada Anda persetujuan Anda, rata-rata waktu penyelesaian, serta antrean yang ditugaskan dan lewat tenggat",
+	"help.digest":               "Mengaktifkan atau menonaktifkan ringkasan review terjadwal untuk channel ini",
+	"help.config":               "Menampilkan pengaturan channel ini, atau mengubah salah satunya (khusus admin). Kunci: digest, require_mention, approve_mode, required_approvals, list_mode; channel tanpa pengaturan khusus memakai nilai bawaan dari environment",
+	"help.help":                 "Menampilkan pesan bantuan ini (juga untuk `queue` saja), atau detail satu perintah, misalnya `queue help add`",
+	"help.reviewers":            "Menampilkan pool reviewer, atau menambah/menghapus reviewer (khusus admin)",
+	"help.snooze":               "Menunda pengingat lewat tenggat sebuah antrean untuk sementara, misalnya 2h atau 1d, atau melanjutkannya dengan off (hanya pemilik atau admin)",
+	"help.next":                 "Merekomendasikan antrean belum diklaim yang paling mendesak di channel ini untuk Anda ambil, dengan tombol untuk mengambilnya",
+	"help.archive":              "Mengarsipkan antrean yang sudah disetujui atau ditutup, menyimpannya sebagai catatan di luar daftar bawaan (hanya pemilik atau admin)",
+	"help.whoami":               "Menampilkan ID pengguna Slack Anda dan tag yang dicocokkan saat menyetujui, untuk memecahkan masalah \"tag tidak ditemukan\"",
+	"help.version":              "Menampilkan versi, commit git, dan versi Go dari bot yang sedang berjalan",
+	"help.checklist":            "Menampilkan atau mengubah checklist review antrean. Pemilik dan admin menambah dan menghapus item; reviewer juga dapat mencentangnya. Kecuali REQUIRE_CHECKLIST bernilai false, antrean tidak dapat disetujui penuh sampai semua item dicentang",
+}
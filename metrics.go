@@ -0,0 +1,81 @@
+package main
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// reviewLatency holds average review latencies derived from the audit log.
+type reviewLatency struct {
+	TimeToFirstReview time.Duration
+	TimeToApproval    time.Duration
+	Reviewed          int
+	Approved          int
+}
+
+// computeReviewLatency averages, per queue created within the entries, the
+// time from creation to the first review claim and to final approval. Queues
+// that never reached a stage are left out of that stage's average rather than
+// counted as zero.
+func computeReviewLatency(entries []AuditEntry) reviewLatency {
+	created := make(map[int]time.Time)
+	firstReview := make(map[int]time.Time)
+	completed := make(map[int]time.Time)
+	for _, entry := range entries {
+		switch entry.Event {
+		case EventQueueAdded:
+			created[entry.QueueID] = entry.Time
+		case EventQueueReviewed:
+			if _, seen := firstReview[entry.QueueID]; !seen {
+				firstReview[entry.QueueID] = entry.Time
+			}
+		case EventQueueCompleted:
+			completed[entry.QueueID] = entry.Time
+		}
+	}
+
+	var latency reviewLatency
+	var reviewTotal, approvalTotal time.Duration
+	for id, createdAt := range created {
+		if reviewedAt, ok := firstReview[id]; ok {
+			reviewTotal += reviewedAt.Sub(createdAt)
+			latency.Reviewed++
+		}
+		if completedAt, ok := completed[id]; ok {
+			approvalTotal += completedAt.Sub(createdAt)
+			latency.Approved++
+		}
+	}
+	if latency.Reviewed > 0 {
+		latency.TimeToFirstReview = reviewTotal / time.Duration(latency.Reviewed)
+	}
+	if latency.Approved > 0 {
+		latency.TimeToApproval = approvalTotal / time.Duration(latency.Approved)
+	}
+	return latency
+}
+
+// RegisterMetrics exposes review latency gauges, computed over the default
+// stats window on every scrape, and the Slack API error counter.
+func (sh *SlackHandler) RegisterMetrics(reg prometheus.Registerer) {
+	latency := func() reviewLatency {
+		return computeReviewLatency(sh.Audit.Since(time.Now().Add(-defaultStatsWindow)))
+	}
+
+	reg.MustRegister(
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "review_queue_time_to_first_review_seconds",
+			Help: "Average time from queue creation to the first review claim over the last 7 days.",
+		}, func() float64 {
+			return latency().TimeToFirstReview.Seconds()
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "review_queue_time_to_approval_seconds",
+			Help: "Average time from queue creation to final approval over the last 7 days.",
+		}, func() float64 {
+			return latency().TimeToApproval.Seconds()
+		}),
+		slackAPIErrors,
+	)
+}
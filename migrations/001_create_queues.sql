@@ -0,0 +1,15 @@
+-- Queues are stored as a JSONB document so new fields don't need a schema
+-- change; the columns alongside it are kept in sync for querying.
+CREATE SEQUENCE IF NOT EXISTS queue_id_seq;
+
+CREATE TABLE IF NOT EXISTS queues (
+    id         INTEGER PRIMARY KEY,
+    channel    TEXT        NOT NULL DEFAULT '',
+    owner      TEXT        NOT NULL DEFAULT '',
+    in_review  BOOLEAN     NOT NULL DEFAULT FALSE,
+    tags       TEXT[]      NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    data       JSONB       NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS queues_channel_idx ON queues (channel);
@@ -0,0 +1,3 @@
+-- Archived queues are kept as a record of finished reviews; NULL means the
+-- queue is not archived.
+ALTER TABLE queues ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ;
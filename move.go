@@ -0,0 +1,79 @@
+package main
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// channelPattern matches a resolved Slack channel reference such as <#C123>
+// or <#C123|general>.
+var channelPattern = regexp.MustCompile(`^<#([CG][A-Z0-9]+)(\|[^>]*)?>$`)
+
+// parseChannel extracts the channel ID from a Slack channel reference.
+func parseChannel(token string) (string, bool) {
+	m := channelPattern.FindStringSubmatch(token)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+func (sh *SlackHandler) handleQueueMove(w http.ResponseWriter, ev *slackevents.MessageEvent) {
+	parts := strings.Fields(ev.Text)
+	if len(parts) != 4 {
+		sh.replyError(ev, t("move.usage"))
+		return
+	}
+	ref := parts[2]
+	if !sh.validQueueID(ref) {
+		sh.replyError(ev, t("queue.invalid_id"))
+		return
+	}
+	target, ok := parseChannel(parts[3])
+	if !ok {
+		sh.replyError(ev, t("move.channel_link"))
+		return
+	}
+
+	// Only post where the bot can actually see replies
+	info, err := sh.API.GetConversationInfo(&slack.GetConversationInfoInput{ChannelID: target})
+	if err != nil || !info.IsMember {
+		sh.replyError(ev, t("move.not_member", target, sh.botUserID()))
+		return
+	}
+
+	sh.mu.Lock()
+	var source string
+	queue, err := sh.findQueue(ref)
+	if err == nil {
+		queue, err = sh.Store.Update(queue.ID, func(q *Queue) error {
+			if q.Channel == target {
+				return rejection(t("move.same_channel", target))
+			}
+			source = q.Channel
+			q.Channel = target
+			q.UpdatedAt = time.Now()
+			return nil
+		})
+	}
+	if err == nil {
+		sh.recordEvent(EventQueueMoved, queue, ev.User)
+	}
+	sh.mu.Unlock()
+
+	if err != nil {
+		sh.replyQueueError(ev, err)
+		return
+	}
+
+	msg := t("move.arrived", queue.DisplayID(), source, ev.User, queue.Title, queue.MRLink, queue.approvalStatus())
+	sh.API.PostMessage(target, slack.MsgOptionText(msg, false))
+
+	notice := t("move.left", queue.DisplayID(), target)
+	sh.API.PostMessage(ev.Channel, slack.MsgOptionText(notice, false))
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// actionTakeQueue is the "Take" button on a queue next recommendation.
+const actionTakeQueue = "take_queue"
+
+// nextCandidates returns the unclaimed queues in the channel the user could
+// pick up, most urgent first: overdue, then due soonest, then oldest. Queues
+// the user owns or has already approved are left out.
+func nextCandidates(queues []*Queue, userID, channel string, now time.Time) []*Queue {
+	tag := fmt.Sprintf("<@%s>", userID)
+	var candidates []*Queue
+	for _, queue := range inChannel(queues, channel) {
+		if queue.status() != StatusOpen || queue.Owner == userID || slices.Contains(queue.Approvers, tag) {
+			continue
+		}
+		candidates = append(candidates, queue)
+	}
+
+	sortQueues(candidates, SortByPriority, now)
+	return candidates
+}
+
+// handleQueueNext recommends the queue the user should pick up next, with a
+// button to take it. A user at the per-reviewer limit is only offered queues
+// they are already tagged on.
+func (sh *SlackHandler) handleQueueNext(w http.ResponseWriter, ev *slackevents.MessageEvent) {
+	now := time.Now()
+	tag := fmt.Sprintf("<@%s>", ev.User)
+
+	sh.mu.Lock()
+	queues, err := sh.teamQueues()
+	var load map[string]int
+	if err == nil {
+		load, err = sh.reviewerLoad()
+	}
+	sh.mu.Unlock()
+
+	if err != nil {
+		sh.replyQueueError(ev, err)
+		return
+	}
+	candidates := nextCandidates(queues, ev.User, ev.Channel, now)
+	atCapacity := sh.Capacity.MaxPerReviewer > 0 && load[tag] >= sh.Capacity.MaxPerReviewer
+	if atCapacity {
+		candidates = slices.DeleteFunc(candidates, func(q *Queue) bool { return !q.hasTag(tag) })
+	}
+	if len(candidates) == 0 {
+		if atCapacity {
+			sh.replyError(ev, t("capacity.you_over", sh.Capacity.MaxPerReviewer))
+		} else {
+			sh.replyError(ev, t("next.none"))
+		}
+		return
+	}
+
+	queue := candidates[0]
+	text := t("next.pick", queue.DisplayID(), queue.Title, queue.Owner, formatDuration(now.Sub(queue.CreatedAt)), queue.MRLink)
+	if queue.isOverdue(now) {
+		text += t("next.overdue", formatDuration(now.Sub(queue.SLADeadline)))
+	} else if !queue.SLADeadline.IsZero() {
+		text += t("queue.due_line", formatTimestamp(queue.SLADeadline, sh.Users.Location(ev.User)))
+	}
+	text = sh.Users.Unmention(text)
+
+	id := queue.DisplayID()
+	blocks := []slack.Block{
+		slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, text, false, false), nil, nil),
+		slack.NewActionBlock("next_"+id,
+			slack.NewButtonBlockElement(actionTakeQueue, id, slack.NewTextBlockObject(slack.PlainTextType, t("button.take"), false, false)).WithStyle(slack.StylePrimary),
+		),
+	}
+	if _, err := sh.API.PostEphemeral(ev.Channel, ev.User, slack.MsgOptionText(text, false), slack.MsgOptionBlocks(blocks...)); err != nil {
+		log.Printf("[ERROR] Failed to post next queue to %s: %v", ev.User, err)
+	}
+}
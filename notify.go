@@ -0,0 +1,97 @@
+package main
+
+import (
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// DMBatcher coalesces the direct messages sent to a user within a short
+// window into one, so an add that tags several people or a burst of approvals
+// doesn't send a ping per event. A non-positive window sends right away.
+type DMBatcher struct {
+	window  time.Duration
+	mu      sync.Mutex
+	pending map[string]*pendingDM // keyed by team and user
+}
+
+// pendingDM is a user's buffered messages, sent together when the window
+// closes. fallbacks run if the combined message can't be delivered.
+type pendingDM struct {
+	api       SlackAPI
+	userID    string
+	messages  []string
+	fallbacks []func()
+}
+
+// NewDMBatcher creates a new instance of DMBatcher.
+func NewDMBatcher(window time.Duration) *DMBatcher {
+	return &DMBatcher{window: window, pending: make(map[string]*pendingDM)}
+}
+
+// Send queues text for the user, starting the window on the first message.
+// fallback, if not nil, runs when the DM fails, e.g. to mention the user in a
+// channel instead.
+func (b *DMBatcher) Send(api SlackAPI, teamID, userID, text string, fallback func()) {
+	dm := &pendingDM{api: api, userID: userID, messages: []string{text}}
+	if fallback != nil {
+		dm.fallbacks = []func(){fallback}
+	}
+	if b.window <= 0 {
+		dm.deliver()
+		return
+	}
+
+	key := teamID + "/" + userID
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if existing, ok := b.pending[key]; ok {
+		existing.messages = append(existing.messages, dm.messages...)
+		existing.fallbacks = append(existing.fallbacks, dm.fallbacks...)
+		return
+	}
+	b.pending[key] = dm
+	time.AfterFunc(b.window, func() { b.flush(key) })
+}
+
+func (b *DMBatcher) flush(key string) {
+	b.mu.Lock()
+	dm, ok := b.pending[key]
+	delete(b.pending, key)
+	b.mu.Unlock()
+	if ok {
+		dm.deliver()
+	}
+}
+
+// Flush sends every buffered message now, e.g. on shutdown.
+func (b *DMBatcher) Flush() {
+	b.mu.Lock()
+	pending := b.pending
+	b.pending = make(map[string]*pendingDM)
+	b.mu.Unlock()
+	for _, dm := range pending {
+		dm.deliver()
+	}
+}
+
+func (dm *pendingDM) deliver() {
+	text := dm.messages[0]
+	if len(dm.messages) > 1 {
+		text = t("notify.batch", len(dm.messages)) + strings.Join(dm.messages, "\n\n")
+	}
+	if _, _, err := dm.api.PostMessage(dm.userID, slack.MsgOptionText(text, false)); err != nil {
+		log.Printf("[WARN] Failed to DM %s (%d notifications): %v", dm.userID, len(dm.messages), err)
+		for _, fallback := range dm.fallbacks {
+			fallback()
+		}
+	}
+}
+
+// notifyUser sends the user a direct message through the batcher.
+func (sh *SlackHandler) notifyUser(userID, text string) {
+	sh.DMs.Send(sh.API, sh.TeamID, userID, text, nil)
+}
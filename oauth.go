@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+
+	"github.com/slack-go/slack"
+)
+
+// oauthScopes are the bot scopes requested when the app is installed.
+const oauthScopes = "app_mentions:read,channels:history,channels:read,chat:write,commands,groups:history,groups:read,im:history,im:read,im:write,pins:write,users:read,users:read.email"
+
+const oauthStateCookie = "oauth_state"
+
+// OAuthConfig enables installing the bot into further workspaces. An empty
+// ClientID keeps the bot in single-token mode.
+type OAuthConfig struct {
+	ClientID     string
+	ClientSecret string
+	// RedirectURL must match a redirect URL configured for the app, e.g.
+	// https://bot.example.com/oauth/callback. Slack's default is used if empty.
+	RedirectURL string
+	TokensPath  string
+}
+
+// TeamToken is the bot credential of one installation.
+type TeamToken struct {
+	BotToken  string `json:"bot_token"`
+	BotUserID string `json:"bot_user_id"`
+}
+
+// TeamTokens is the persisted set of bot tokens, keyed by team ID.
+type TeamTokens struct {
+	path   string
+	tokens map[string]TeamToken
+	mu     sync.Mutex
+}
+
+// NewTeamTokens creates a new instance of TeamTokens, loading any tokens
+// previously saved at path. An empty path keeps them in memory only.
+func NewTeamTokens(path string) (*TeamTokens, error) {
+	tt := &TeamTokens{path: path, tokens: make(map[string]TeamToken)}
+	if path == "" {
+		return tt, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return tt, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read team tokens: %w", err)
+	}
+	if err := json.Unmarshal(data, &tt.tokens); err != nil {
+		return nil, fmt.Errorf("parse team tokens: %w", err)
+	}
+	return tt, nil
+}
+
+// Get returns the team's token, if it installed the bot.
+func (tt *TeamTokens) Get(teamID string) (TeamToken, bool) {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+
+	token, ok := tt.tokens[teamID]
+	return token, ok
+}
+
+// Set stores the team's token, replacing any earlier installation.
+func (tt *TeamTokens) Set(teamID string, token TeamToken) error {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+
+	tt.tokens[teamID] = token
+	if tt.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(tt.tokens, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(tt.path, data)
+}
+
+// forTeam returns the handler that talks to the team's workspace. Handlers
+// for other teams share the queue state and lock but have their own client,
+// bot user and user directory. Unknown or empty teams get sh.
+func (sh *SlackHandler) forTeam(teamID string) *SlackHandler {
+	if teamID == "" || sh.Teams == nil {
+		return sh
+	}
+	if th, ok := sh.teams.Load(teamID); ok {
+		return th.(*SlackHandler)
+	}
+
+	token, ok := sh.Teams.Get(teamID)
+	if !ok {
+		log.Printf("[WARN] No bot token for team %s; using the default workspace", teamID)
+		return sh
+	}
+	api := NewSlackAPI(token.BotToken)
+	th := *sh
+	th.API = api
+	th.TeamID = teamID
+	th.BotUserID = token.BotUserID
+	th.Users = NewUserDirectory(api)
+	actual, _ := sh.teams.LoadOrStore(teamID, &th)
+	return actual.(*SlackHandler)
+}
+
+// anyTeam returns a handler that sees the queues of every workspace, for
+// callers not tied to one such as the HTTP API.
+func (sh *SlackHandler) anyTeam() *SlackHandler {
+	th := *sh
+	th.TeamID = ""
+	return &th
+}
+
+// ownsTeam reports whether data recorded for the team belongs to this
+// handler's workspace. Queues saved before multi-workspace support have no
+// team and belong to the workspace of SLACK_BOT_TOKEN.
+func (sh *SlackHandler) ownsTeam(teamID string) bool {
+	if sh.TeamID == "" {
+		return true
+	}
+	if teamID == "" {
+		teamID = sh.homeTeam
+	}
+	return teamID == sh.TeamID
+}
+
+// teamQueues lists the queues of this handler's workspace. Commands must
+// use it rather than Store.List so that workspaces cannot see or change each
+// other's queues. Callers must hold sh.mu.
+func (sh *SlackHandler) teamQueues() ([]*Queue, error) {
+	queues, err := sh.Store.List()
+	if err != nil {
+		return nil, err
+	}
+	owned := queues[:0:0]
+	for _, queue := range queues {
+		if sh.ownsTeam(queue.TeamID) {
+			owned = append(owned, queue)
+		}
+	}
+	return owned, nil
+}
+
+// teamEntries keeps the audit entries of this handler's workspace.
+func (sh *SlackHandler) teamEntries(entries []AuditEntry) []AuditEntry {
+	var owned []AuditEntry
+	for _, entry := range entries {
+		if sh.ownsTeam(entry.TeamID) {
+			owned = append(owned, entry)
+		}
+	}
+	return owned
+}
+
+// HandleOAuthInstall redirects to Slack's consent page to install the bot
+// into a workspace.
+func (sh *SlackHandler) HandleOAuthInstall(w http.ResponseWriter, r *http.Request) {
+	state := make([]byte, 16)
+	if _, err := rand.Read(state); err != nil {
+		log.Printf("[ERROR] Failed to generate OAuth state: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     oauthStateCookie,
+		Value:    hex.EncodeToString(state),
+		Path:     "/oauth",
+		MaxAge:   600,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	query := url.Values{
+		"client_id": {sh.OAuth.ClientID},
+		"scope":     {oauthScopes},
+		"state":     {hex.EncodeToString(state)},
+	}
+	if sh.OAuth.RedirectURL != "" {
+		query.Set("redirect_uri", sh.OAuth.RedirectURL)
+	}
+	http.Redirect(w, r, "https://slack.com/oauth/v2/authorize?"+query.Encode(), http.StatusFound)
+}
+
+// HandleOAuthCallback exchanges the code from Slack's consent page for the
+// team's bot token and stores it.
+func (sh *SlackHandler) HandleOAuthCallback(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	cookie, err := r.Cookie(oauthStateCookie)
+	if err != nil || cookie.Value == "" || cookie.Value != query.Get("state") {
+		log.Printf("[WARN] Rejected OAuth callback with a missing or mismatched state")
+		http.Error(w, "Invalid or expired install link. Please start the installation again.", http.StatusBadRequest)
+		return
+	}
+	if reason := query.Get("error"); reason != "" {
+		http.Error(w, "Installation was cancelled: "+reason, http.StatusBadRequest)
+		return
+	}
+
+	// The install runs in the browser's request, so it is bounded by it too
+	ctx, cancel := context.WithTimeout(r.Context(), slackTimeout)
+	defer cancel()
+	resp, err := slack.GetOAuthV2ResponseContext(ctx, http.DefaultClient, sh.OAuth.ClientID, sh.OAuth.ClientSecret, query.Get("code"), sh.OAuth.RedirectURL)
+	if err != nil {
+		log.Printf("[ERROR] Failed to exchange OAuth code: %v", err)
+		http.Error(w, "Couldn't complete the installation with Slack. Please try again.", http.StatusBadGateway)
+		return
+	}
+	token := TeamToken{BotToken: resp.AccessToken, BotUserID: resp.BotUserID}
+	if err := sh.Teams.Set(resp.Team.ID, token); err != nil {
+		log.Printf("[ERROR] Failed to save bot token for team %s: %v", resp.Team.ID, err)
+		http.Error(w, "Couldn't save the installation. Please try again.", http.StatusInternalServerError)
+		return
+	}
+	// A reinstall may have issued a new token
+	sh.teams.Delete(resp.Team.ID)
+
+	log.Printf("[INFO] Installed in team %s (%s)", resp.Team.ID, resp.Team.Name)
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "Installed in %s. You can close this page.", resp.Team.Name)
+}
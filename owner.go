@@ -0,0 +1,54 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// handleQueueOwner hands a queue over to a new owner, e.g. when the author
+// leaves or passes the change on.
+func (sh *SlackHandler) handleQueueOwner(w http.ResponseWriter, ev *slackevents.MessageEvent) {
+	ref, tag, err := sh.parseAssignArgs(ev.Text)
+	if err != nil {
+		sh.replyError(ev, err.Error())
+		return
+	}
+	newOwner, _ := parseMention(tag)
+
+	sh.mu.Lock()
+	var previous string
+	queue, err := sh.findQueue(ref)
+	if err == nil {
+		queue, err = sh.Store.Update(queue.ID, func(q *Queue) error {
+			if q.Owner != ev.User && !sh.isAdmin(ev.User) {
+				return rejection(t("owner.owner_only", q.Owner))
+			}
+			if q.Owner == newOwner {
+				return rejection(t("owner.already", newOwner))
+			}
+			previous = q.Owner
+			q.Owner = newOwner
+			q.UpdatedAt = time.Now()
+			return nil
+		})
+	}
+	if err == nil {
+		sh.recordEvent(EventQueueTransferred, queue, ev.User)
+		sh.refreshUserHomes(queue.TeamID, previous)
+		sh.refreshStatusCard(queue)
+	}
+	sh.mu.Unlock()
+
+	if err != nil {
+		sh.replyQueueError(ev, err)
+		return
+	}
+	msg := t("owner.done", queue.DisplayID(), tag)
+	sh.API.PostMessage(ev.Channel, slack.MsgOptionText(msg, false))
+
+	notice := t("owner.notice", ev.User, queue.DisplayID(), queue.Title, queue.MRLink)
+	sh.notifyUser(newOwner, notice)
+}
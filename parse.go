@@ -0,0 +1,268 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// splitArgs splits a command into whitespace-separated tokens, keeping text
+// inside double quotes (including Slack's curly quotes) together. Newlines
+// inside quotes are preserved.
+func splitArgs(text string) []string {
+	var (
+		args    []string
+		current strings.Builder
+		inQuote bool
+		quoted  bool
+	)
+
+	flush := func() {
+		if current.Len() > 0 || quoted {
+			args = append(args, current.String())
+		}
+		current.Reset()
+		quoted = false
+	}
+
+	for _, r := range text {
+		switch {
+		case r == '"' || r == '“' || r == '”':
+			inQuote = !inQuote
+			quoted = true
+		case unicode.IsSpace(r) && !inQuote:
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+
+	return args
+}
+
+// splitList splits a comma-separated setting into trimmed, non-empty entries.
+// Slack mention markup such as <@U123> is reduced to the bare ID.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		item = strings.TrimSuffix(strings.TrimPrefix(item, "<@"), ">")
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseFlags separates `--name=value` and `--name value` flags from
+// positional arguments. Only flags listed in valueFlags consume the following
+// token as their value; any other flag is treated as a boolean set to "true".
+func parseFlags(args []string, valueFlags ...string) ([]string, map[string]string) {
+	takesValue := make(map[string]bool, len(valueFlags))
+	for _, name := range valueFlags {
+		takesValue[name] = true
+	}
+
+	var positional []string
+	flags := make(map[string]string)
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "--") || len(arg) == 2 {
+			positional = append(positional, arg)
+			continue
+		}
+
+		name := strings.TrimPrefix(arg, "--")
+		if eq := strings.Index(name, "="); eq != -1 {
+			flags[name[:eq]] = name[eq+1:]
+			continue
+		}
+		if takesValue[name] && i+1 < len(args) {
+			flags[name] = args[i+1]
+			i++
+			continue
+		}
+		flags[name] = "true"
+	}
+
+	return positional, flags
+}
+
+// formatDescription renders a free-form description as a Slack block quote so
+// that multi-line text and stray markup stay contained under its queue.
+func formatDescription(desc string) string {
+	desc = strings.ReplaceAll(desc, "\r\n", "\n")
+	// A stray code fence would swallow every line that follows it.
+	desc = strings.ReplaceAll(desc, "```", "`\u200b`\u200b`")
+
+	var b strings.Builder
+	for _, line := range strings.Split(strings.TrimSpace(desc), "\n") {
+		b.WriteString("> ")
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// looksLikeURL reports whether arg is a link, either bare or in Slack's
+// <https://...> or <https://...|label> form.
+func looksLikeURL(arg string) bool {
+	arg = strings.ToLower(strings.TrimPrefix(arg, "<"))
+	return strings.HasPrefix(arg, "http://") || strings.HasPrefix(arg, "https://")
+}
+
+// TextLimits are the minimum lengths, in characters, of queue titles and
+// descriptions. Zero disables a check.
+type TextLimits struct {
+	MinTitle       int
+	MinDescription int
+}
+
+// checkTitle rejects a title shorter than MinTitle.
+func (tl TextLimits) checkTitle(title string) error {
+	if utf8.RuneCountInString(strings.TrimSpace(title)) < tl.MinTitle {
+		return errors.New(t("text.title_short", tl.MinTitle))
+	}
+	return nil
+}
+
+// checkDescription rejects a description shorter than MinDescription. An
+// empty description is always allowed, since descriptions are optional.
+func (tl TextLimits) checkDescription(description string) error {
+	n := utf8.RuneCountInString(strings.TrimSpace(description))
+	if n > 0 && n < tl.MinDescription {
+		return errors.New(t("text.description_short", tl.MinDescription))
+	}
+	return nil
+}
+
+// MRLinkConfig expands bare merge request references given to `queue add`
+// into full links. An empty base URL leaves that form of reference alone.
+type MRLinkConfig struct {
+	// GitLabBaseURL prefixes !123, e.g.
+	// https://gitlab.example.com/group/repo/-/merge_requests.
+	GitLabBaseURL string
+	// GitHubBaseURL prefixes #123, e.g. https://github.com/org/repo/pull.
+	GitHubBaseURL string
+}
+
+// mrRefPattern matches a bare GitLab (!123) or GitHub (#123) reference.
+var mrRefPattern = regexp.MustCompile(`^([!#])([0-9]+)$`)
+
+// expand returns the full link for a bare reference, or ref unchanged.
+func (c MRLinkConfig) expand(ref string) string {
+	m := mrRefPattern.FindStringSubmatch(ref)
+	if m == nil {
+		return ref
+	}
+	base := c.GitLabBaseURL
+	if m[1] == "#" {
+		base = c.GitHubBaseURL
+	}
+	if base == "" {
+		return ref
+	}
+	return strings.TrimSuffix(base, "/") + "/" + m[2]
+}
+
+// normalizeMRLink reduces a link to a form two spellings of the same merge
+// request share: Slack's <...|label> wrapping, the query, fragment and
+// trailing slash are dropped and the scheme and host are lowercased.
+func normalizeMRLink(link string) string {
+	link = strings.TrimSuffix(strings.TrimPrefix(link, "<"), ">")
+	link, _, _ = strings.Cut(link, "|")
+	link = strings.ReplaceAll(link, "&amp;", "&")
+	link, _, _ = strings.Cut(link, "#")
+	link, _, _ = strings.Cut(link, "?")
+	link = strings.TrimRight(link, "/")
+
+	scheme, rest, ok := strings.Cut(link, "://")
+	if !ok {
+		return link
+	}
+	host, path, _ := strings.Cut(rest, "/")
+	if path == "" {
+		return strings.ToLower(scheme + "://" + host)
+	}
+	return strings.ToLower(scheme+"://"+host) + "/" + path
+}
+
+// slackTokenPattern matches the <...> tokens Slack itself puts in message
+// text: mentions, channel links, special commands and URLs.
+var slackTokenPattern = regexp.MustCompile(`<(?:[@#!][^<>]*|(?:https?|mailto):[^<>]*)>`)
+
+// broadcastPattern matches @channel, @here and @everyone in Slack's token
+// form, with or without a label.
+var broadcastPattern = regexp.MustCompile(`<!(channel|here|everyone)(\|[^>]*)?>`)
+
+// subteamPattern matches a user group mention, with or without a label.
+var subteamPattern = regexp.MustCompile(`<!subteam\^[^|>]*(?:\|@?([^>]*))?>`)
+
+// entityPattern matches one of the three escapes Slack uses in message text
+// at the start of a string.
+var entityPattern = regexp.MustCompile(`^&(amp|lt|gt);`)
+
+// sanitize makes user-supplied text safe to echo in a message. Broadcast and
+// user group mentions become inert text unless allowBroadcast is set, and any
+// &, < or > that is not already part of a Slack token or escape is escaped,
+// so it can't open a mention or break the markup.
+func sanitize(text string, allowBroadcast bool) string {
+	if !allowBroadcast {
+		text = broadcastPattern.ReplaceAllString(text, "@$1")
+		text = subteamPattern.ReplaceAllStringFunc(text, func(mention string) string {
+			if name := subteamPattern.FindStringSubmatch(mention)[1]; name != "" {
+				return "@" + name
+			}
+			return "@group"
+		})
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, loc := range slackTokenPattern.FindAllStringIndex(text, -1) {
+		b.WriteString(escapeText(text[last:loc[0]]))
+		b.WriteString(text[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	b.WriteString(escapeText(text[last:]))
+	return b.String()
+}
+
+// escapeText applies Slack's escaping to text outside of tokens, leaving
+// existing escapes alone.
+func escapeText(text string) string {
+	var b strings.Builder
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; {
+		case c == '&' && entityPattern.MatchString(text[i:min(i+5, len(text))]):
+			b.WriteByte(c)
+		case c == '&':
+			b.WriteString("&amp;")
+		case c == '<':
+			b.WriteString("&lt;")
+		case c == '>':
+			b.WriteString("&gt;")
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// parseSize validates a --size value: a t-shirt size (S, M, L or XL, in any
+// case) or a positive line count, which is stored as e.g. "120 lines".
+func parseSize(value string) (string, error) {
+	switch size := strings.ToUpper(value); size {
+	case "S", "M", "L", "XL":
+		return size, nil
+	}
+	if lines, err := strconv.Atoi(value); err == nil && lines > 0 {
+		return fmt.Sprintf("%d lines", lines), nil
+	}
+	return "", errors.New(t("size.invalid", value))
+}
@@ -0,0 +1,75 @@
+package main
+
+import "testing"
+
+func TestTextLimits(t *testing.T) {
+	limits := TextLimits{MinTitle: 3, MinDescription: 5}
+	titles := []struct {
+		title string
+		ok    bool
+	}{
+		{"", false},
+		{"ab", false},
+		{"  ab  ", false},
+		{"abc", true},
+		{"abcd", true},
+		{"äöü", true},
+		{"日本", false},
+	}
+	for _, tt := range titles {
+		if err := limits.checkTitle(tt.title); (err == nil) != tt.ok {
+			t.Errorf("checkTitle(%q) = %v, want ok %v", tt.title, err, tt.ok)
+		}
+	}
+
+	descriptions := []struct {
+		description string
+		ok          bool
+	}{
+		{"", true},
+		{"   ", true},
+		{"abcd", false},
+		{"abcde", true},
+		{" abcd ", false},
+		{"abcdef", true},
+	}
+	for _, tt := range descriptions {
+		if err := limits.checkDescription(tt.description); (err == nil) != tt.ok {
+			t.Errorf("checkDescription(%q) = %v, want ok %v", tt.description, err, tt.ok)
+		}
+	}
+
+	var disabled TextLimits
+	if err := disabled.checkTitle(""); err != nil {
+		t.Errorf("zero MinTitle rejected an empty title: %v", err)
+	}
+	if err := disabled.checkDescription("a"); err != nil {
+		t.Errorf("zero MinDescription rejected a description: %v", err)
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		text           string
+		allowBroadcast bool
+		want           string
+	}{
+		{"plain text", false, "plain text"},
+		{"a < b & c", false, "a &lt; b &amp; c"},
+		{"already &lt;escaped&gt;", false, "already &lt;escaped&gt;"},
+		{"ping <@U123>", false, "ping <@U123>"},
+		{"see <https://example.com|the MR>", false, "see <https://example.com|the MR>"},
+		{"hey <!here>", false, "hey @here"},
+		{"hey <!channel|@channel>", false, "hey @channel"},
+		{"hey <!here>", true, "hey <!here>"},
+		{"hey <!subteam^S123|@backend>", false, "hey @backend"},
+		{"hey <!subteam^S123|backend>", false, "hey @backend"},
+		{"hey <!subteam^S123>", false, "hey @group"},
+		{"hey <!subteam^S123|@backend>", true, "hey <!subteam^S123|@backend>"},
+	}
+	for _, tt := range tests {
+		if got := sanitize(tt.text, tt.allowBroadcast); got != tt.want {
+			t.Errorf("sanitize(%q, %v) = %q, want %q", tt.text, tt.allowBroadcast, got, tt.want)
+		}
+	}
+}
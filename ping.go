@@ -0,0 +1,50 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// pingInterval is how often a queue's reviewers can be pinged on demand.
+const pingInterval = 10 * time.Minute
+
+// handleQueuePing reminds a queue's pending reviewers without waiting for the
+// scheduled reminder.
+func (sh *SlackHandler) handleQueuePing(w http.ResponseWriter, ev *slackevents.MessageEvent) {
+	ref, err := sh.parseQueueID(ev.Text)
+	if err != nil {
+		sh.replyError(ev, err.Error())
+		return
+	}
+
+	now := time.Now()
+	sh.mu.Lock()
+	queue, err := sh.findQueue(ref)
+	if err == nil {
+		queue, err = sh.Store.Update(queue.ID, func(q *Queue) error {
+			if err := q.checkActive(); err != nil {
+				return err
+			}
+			if len(q.pendingTags()) == 0 {
+				return rejection(t("ping.none_pending"))
+			}
+			if now.Sub(q.LastPingedAt) < pingInterval {
+				return rejection(t("ping.throttled", formatDuration(pingInterval-now.Sub(q.LastPingedAt))))
+			}
+			q.LastPingedAt = now
+			return nil
+		})
+	}
+	sh.mu.Unlock()
+
+	if err != nil {
+		sh.replyQueueError(ev, err)
+		return
+	}
+	msg := t("ping.message", ev.User, queue.DisplayID(), queue.Title, strings.Join(queue.pendingTags(), " "), queue.MRLink)
+	sh.API.PostMessage(ev.Channel, slack.MsgOptionText(msg, false))
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"log"
+
+	"github.com/slack-go/slack"
+)
+
+// pinReviewMessage pins the "now in review" message and remembers it on the
+// queue so it can be unpinned later. Failures such as a full pin list or a
+// missing pins:write scope are logged and otherwise ignored. It calls the
+// Slack API, so call it without holding sh.mu.
+func (sh *SlackHandler) pinReviewMessage(id int, channel, ts string) {
+	if !sh.PinReviews || ts == "" {
+		return
+	}
+	if err := sh.API.AddPin(channel, slack.NewRefToMessage(channel, ts)); err != nil {
+		log.Printf("[WARN] Failed to pin review message for queue %d: %v", id, err)
+		return
+	}
+
+	var previous reviewPin
+	_, err := sh.Store.Update(id, func(q *Queue) error {
+		previous = takeReviewPin(q) // a previous claim's pin, if any
+		q.PinnedChannel = channel
+		q.PinnedTS = ts
+		return nil
+	})
+	if err != nil {
+		log.Printf("[WARN] Failed to record pinned message for queue %d: %v", id, err)
+		go sh.removePin(id, channel, ts)
+		return
+	}
+	sh.unpinReviewMessage(previous)
+}
+
+// reviewPin is a pinned "now in review" message.
+type reviewPin struct {
+	id      int
+	channel string
+	ts      string
+}
+
+// takeReviewPin clears the queue's pinned review message and returns it, to
+// be unpinned once the change to q is saved.
+func takeReviewPin(q *Queue) reviewPin {
+	pin := reviewPin{id: q.ID, channel: q.PinnedChannel, ts: q.PinnedTS}
+	q.PinnedChannel = ""
+	q.PinnedTS = ""
+	return pin
+}
+
+// unpinReviewMessage unpins a message taken with takeReviewPin in the
+// background.
+func (sh *SlackHandler) unpinReviewMessage(pin reviewPin) {
+	if pin.ts == "" {
+		return
+	}
+	go sh.removePin(pin.id, pin.channel, pin.ts)
+}
+
+func (sh *SlackHandler) removePin(id int, channel, ts string) {
+	if err := sh.API.RemovePin(channel, slack.NewRefToMessage(channel, ts)); err != nil {
+		log.Printf("[WARN] Failed to unpin review message for queue %d: %v", id, err)
+	}
+}
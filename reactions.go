@@ -0,0 +1,198 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// ReactionConfig maps emoji reactions on a queue's "added" message to
+// review actions. Names are given without colons, e.g. white_check_mark.
+type ReactionConfig struct {
+	// Approve approves the queue; removing the reaction withdraws the
+	// approval.
+	Approve []string
+	// RequestChanges sends the queue back to open and notifies the owner.
+	RequestChanges []string
+}
+
+// parseReactions splits a comma-separated list of emoji names, accepting
+// them with or without colons.
+func parseReactions(value string) []string {
+	var names []string
+	for _, name := range splitList(value) {
+		if name = strings.Trim(name, ":"); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// trackReactions remembers the queue's "added" message, so reactions to it
+// can be matched back to the queue. It runs once the message has been posted,
+// so call it without holding sh.mu.
+func (sh *SlackHandler) trackReactions(queue *Queue, channel, ts string) {
+	if len(sh.Reactions.Approve) == 0 && len(sh.Reactions.RequestChanges) == 0 {
+		return
+	}
+	_, err := sh.Store.Update(queue.ID, func(q *Queue) error {
+		q.StatusChannel = channel
+		q.ThreadTS = ts
+		return nil
+	})
+	if err != nil {
+		log.Printf("[WARN] Failed to record message of queue %s: %v", queue.DisplayID(), err)
+	}
+}
+
+// queueByMessage returns the queue whose "added" message is channel/ts, or
+// nil if there is none. Callers must hold sh.mu.
+func (sh *SlackHandler) queueByMessage(channel, ts string) (*Queue, error) {
+	queues, err := sh.teamQueues()
+	if err != nil {
+		return nil, err
+	}
+	for _, queue := range queues {
+		if queue.ThreadTS == ts && queue.StatusChannel == channel {
+			return queue, nil
+		}
+	}
+	return nil, nil
+}
+
+func (sh *SlackHandler) handleReactionAdded(ev *slackevents.ReactionAddedEvent) {
+	approve := slices.Contains(sh.Reactions.Approve, ev.Reaction)
+	changes := slices.Contains(sh.Reactions.RequestChanges, ev.Reaction)
+	if ev.Item.Type != "message" || (!approve && !changes) {
+		return
+	}
+
+	sh.mu.Lock()
+	queue, err := sh.queueByMessage(ev.Item.Channel, ev.Item.Timestamp)
+	if err != nil || queue == nil {
+		sh.mu.Unlock()
+		if err != nil {
+			log.Printf("[ERROR] Failed to look up queue for reaction: %v", err)
+		}
+		return
+	}
+	var msg string
+	if approve {
+		msg, queue, err = sh.approveQueueID(queue.ID, ev.User)
+	} else {
+		queue, err = sh.requestChanges(queue.ID, ev.User)
+	}
+	sh.mu.Unlock()
+
+	if err != nil {
+		// Explain only to the person who reacted
+		sh.API.PostEphemeral(ev.Item.Channel, ev.User, slack.MsgOptionText(queueErrorSummary(err), false))
+		return
+	}
+
+	thread := slack.MsgOptionTS(ev.Item.Timestamp)
+	if changes {
+		text := t("reaction.changes", ev.Reaction, ev.User, queue.DisplayID())
+		sh.API.PostMessage(ev.Item.Channel, slack.MsgOptionText(text, false), thread)
+		notice := t("reaction.changes_notice", ev.User, queue.DisplayID(), queue.Title, queue.MRLink)
+		sh.notifyUser(queue.Owner, notice)
+		return
+	}
+
+	text := t("reaction.approved", ev.Reaction, ev.User, msg)
+	sh.API.PostMessage(ev.Item.Channel, slack.MsgOptionText(text, false), thread)
+	if queue.status() == StatusApproved && queue.Owner != ev.User {
+		sh.notifyOwnerApproved(queue)
+	}
+}
+
+func (sh *SlackHandler) handleReactionRemoved(ev *slackevents.ReactionRemovedEvent) {
+	if ev.Item.Type != "message" || !slices.Contains(sh.Reactions.Approve, ev.Reaction) {
+		return
+	}
+
+	sh.mu.Lock()
+	withdrawn := false
+	queue, err := sh.queueByMessage(ev.Item.Channel, ev.Item.Timestamp)
+	if err == nil && queue != nil {
+		queue, err = sh.Store.Update(queue.ID, func(q *Queue) error {
+			withdrawn = withdrawApproval(q, ev.User)
+			if withdrawn {
+				q.UpdatedAt = time.Now()
+			}
+			return nil
+		})
+	}
+	if err == nil && withdrawn {
+		sh.refreshHomes(queue)
+		sh.refreshStatusCard(queue)
+	}
+	sh.mu.Unlock()
+
+	if err != nil {
+		log.Printf("[ERROR] Failed to withdraw approval of %s: %v", ev.User, err)
+		return
+	}
+	if withdrawn {
+		text := t("reaction.withdrawn", ev.User, queue.DisplayID())
+		sh.API.PostMessage(ev.Item.Channel, slack.MsgOptionText(text, false), slack.MsgOptionTS(ev.Item.Timestamp))
+	}
+}
+
+// requestChanges sends a queue back to open, releasing any review claim.
+// Callers must hold sh.mu.
+func (sh *SlackHandler) requestChanges(id int, userID string) (*Queue, error) {
+	var released string
+	var pin reviewPin
+	queue, err := sh.Store.Update(id, func(q *Queue) error {
+		released, pin = "", reviewPin{}
+		if q.Owner == userID {
+			return rejection(t("reaction.own_queue"))
+		}
+		if err := q.checkActive(); err != nil {
+			return err
+		}
+		if q.status() == StatusInReview {
+			if err := q.transition(StatusOpen); err != nil {
+				return err
+			}
+			released = q.Reviewer
+			q.Reviewer = ""
+			pin = takeReviewPin(q)
+		}
+		q.UpdatedAt = time.Now()
+		return nil
+	})
+	if err == nil {
+		sh.unpinReviewMessage(pin)
+		sh.refreshHomes(queue)
+		sh.refreshUserHomes(queue.TeamID, released)
+		sh.refreshStatusCard(queue)
+	}
+	return queue, err
+}
+
+// withdrawApproval takes back the user's approval of the queue, returning
+// whether there was one. In tag mode the user becomes a pending reviewer
+// again, and a completed queue reopens.
+func withdrawApproval(q *Queue, userID string) bool {
+	tag := fmt.Sprintf("<@%s>", userID)
+	i := slices.Index(q.Approvers, tag)
+	if i == -1 || q.status() == StatusClosed || q.isArchived() {
+		return false
+	}
+	q.Approvers = slices.Delete(q.Approvers, i, i+1)
+	if q.RequiredApprovals == 0 {
+		q.Tags = append(q.Tags, tag)
+	}
+	if q.status() == StatusApproved {
+		q.transition(StatusOpen)
+		q.ApprovedAt = time.Time{}
+	}
+	return true
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// ReminderConfig controls the overdue reminder loop.
+type ReminderConfig struct {
+	// Interval between checks; a queue is re-pinged at most once per interval.
+	Interval time.Duration
+	// EscalateAfter is the number of unanswered reminders after which the
+	// queue is escalated. Zero or an empty EscalationTarget disables
+	// escalation.
+	EscalateAfter int
+	// EscalationTarget is a user mention (<@U123> or U123) or channel
+	// (<#C123> or C123) to escalate to.
+	EscalationTarget string
+	// Template renders the reminder text from a reminderData.
+	Template *template.Template
+}
+
+// defaultReminderTemplate is used when REMINDER_TEMPLATE is unset or invalid.
+const defaultReminderTemplate = ":alarm_clock: Queue {{.ID}} *{{.Title}}* is overdue by {{.Overdue}}. {{.Tags}} please take a look: {{.MRLink}}"
+
+var defaultReminder = template.Must(template.New("reminder").Parse(defaultReminderTemplate))
+
+// reminderData is the data REMINDER_TEMPLATE is rendered with.
+type reminderData struct {
+	ID      string
+	Title   string
+	MRLink  string
+	Tags    string // pending reviewer mentions, space separated
+	Owner   string // owner mention
+	Age     string // time since the queue was created
+	Overdue string // time past the SLA
+}
+
+// parseReminderTemplate parses text and renders it once against sample data,
+// so missing fields are caught at startup rather than on the first reminder.
+func parseReminderTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("reminder").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	if err := tmpl.Execute(&strings.Builder{}, reminderData{}); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// reminderText renders the reminder for an overdue queue, falling back to the
+// default template if the configured one fails.
+func (cfg ReminderConfig) reminderText(q *Queue, now time.Time) string {
+	data := reminderData{
+		ID:      q.DisplayID(),
+		Title:   q.Title,
+		MRLink:  q.MRLink,
+		Tags:    strings.Join(q.pendingTags(), " "),
+		Owner:   fmt.Sprintf("<@%s>", q.Owner),
+		Age:     formatDuration(now.Sub(q.CreatedAt)),
+		Overdue: formatDuration(now.Sub(q.SLADeadline)),
+	}
+
+	tmpl := cfg.Template
+	if tmpl == nil {
+		tmpl = defaultReminder
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		log.Printf("[WARN] Failed to render reminder for queue %s, using the default: %v", q.DisplayID(), err)
+		b.Reset()
+		defaultReminder.Execute(&b, data)
+	}
+	return b.String()
+}
+
+// StartReminders launches a background loop that re-pings the reviewers of
+// queues that are past their SLA. A non-positive interval disables it.
+func (sh *SlackHandler) StartReminders(cfg ReminderConfig) {
+	if cfg.Interval <= 0 {
+		log.Printf("[INFO] Reminders disabled")
+		return
+	}
+	if cfg.EscalationTarget == "" {
+		cfg.EscalateAfter = 0
+	}
+
+	log.Printf("[INFO] Checking for overdue queues every %s", cfg.Interval)
+	go func() {
+		ticker := time.NewTicker(cfg.Interval)
+		defer ticker.Stop()
+		for now := range ticker.C {
+			sh.remindOverdue(now, cfg)
+		}
+	}()
+}
+
+// resetReminders starts the reminder count over after someone acts on the
+// queue, so escalation only follows reminders that went unanswered.
+func (q *Queue) resetReminders() {
+	q.ReminderCount = 0
+	q.LastEscalatedAt = time.Time{}
+}
+
+type reminder struct {
+	team    string
+	channel string
+	text    string
+}
+
+// remindOverdue pings the remaining tags of every overdue queue, at most once
+// per interval per queue, and escalates queues that have gone unanswered for
+// cfg.EscalateAfter reminders.
+func (sh *SlackHandler) remindOverdue(now time.Time, cfg ReminderConfig) {
+	var reminders []reminder
+
+	sh.mu.Lock()
+	queues, err := sh.Store.List()
+	if err != nil {
+		sh.mu.Unlock()
+		log.Printf("[ERROR] Failed to list queues for reminders: %v", err)
+		return
+	}
+	for _, queue := range queues {
+		if !queue.isOverdue(now) || !queue.isActive() || len(queue.pendingTags()) == 0 || queue.Channel == "" {
+			continue
+		}
+		if queue.isSnoozed(now) || now.Sub(queue.LastRemindedAt) < cfg.Interval {
+			continue
+		}
+
+		var escalate bool
+		updated, err := sh.Store.Update(queue.ID, func(q *Queue) error {
+			q.LastRemindedAt = now
+			q.ReminderCount++
+			// Escalate again only after another full round of reminders
+			escalate = cfg.EscalateAfter > 0 && q.ReminderCount >= cfg.EscalateAfter &&
+				now.Sub(q.LastEscalatedAt) >= time.Duration(cfg.EscalateAfter)*cfg.Interval
+			if escalate {
+				q.LastEscalatedAt = now
+			}
+			return nil
+		})
+		if err != nil {
+			log.Printf("[ERROR] Failed to record reminder for queue %s: %v", queue.DisplayID(), err)
+			continue
+		}
+
+		text := cfg.reminderText(updated, now)
+		reminders = append(reminders, reminder{team: updated.TeamID, channel: updated.Channel, text: text})
+		if escalate {
+			escalated := escalation(cfg.EscalationTarget, updated, formatDuration(now.Sub(updated.SLADeadline)))
+			escalated.team = updated.TeamID
+			reminders = append(reminders, escalated)
+		}
+	}
+	sh.mu.Unlock()
+
+	for _, r := range groupReminders(reminders) {
+		if _, _, err := sh.forTeam(r.team).API.PostMessage(r.channel, slack.MsgOptionText(r.text, false)); err != nil {
+			log.Printf("[ERROR] Failed to post reminder to %s: %v", r.channel, err)
+		}
+	}
+}
+
+// groupReminders merges the reminders bound for the same channel into one
+// message, so a channel with several overdue queues gets a single post.
+func groupReminders(reminders []reminder) []reminder {
+	var grouped []reminder
+	index := make(map[string]int)
+	for _, r := range reminders {
+		key := r.team + "/" + r.channel
+		if i, ok := index[key]; ok {
+			grouped[i].text += "\n" + r.text
+			continue
+		}
+		index[key] = len(grouped)
+		grouped = append(grouped, r)
+	}
+	return grouped
+}
+
+// escalation builds the escalation message for a queue. Channel targets get
+// the message posted to them; user targets are mentioned in the queue's
+// channel.
+func escalation(target string, queue *Queue, overdue string) reminder {
+	text := t("reminder.escalation", queue.DisplayID(), queue.Title, overdue, queue.ReminderCount, strings.Join(queue.pendingTags(), " "), queue.MRLink)
+
+	if channelID, ok := strings.CutPrefix(target, "<#"); ok {
+		channelID, _, _ = strings.Cut(strings.TrimSuffix(channelID, ">"), "|")
+		return reminder{channel: channelID, text: text}
+	}
+	if strings.HasPrefix(target, "C") || strings.HasPrefix(target, "G") {
+		return reminder{channel: target, text: text}
+	}
+
+	userID := strings.TrimSuffix(strings.TrimPrefix(target, "<@"), ">")
+	return reminder{channel: queue.Channel, text: fmt.Sprintf("<@%s> %s", userID, text)}
+}
+
+// isOverdue reports whether the queue has an SLA that has passed.
+func (q *Queue) isOverdue(now time.Time) bool {
+	return !q.SLADeadline.IsZero() && now.After(q.SLADeadline)
+}
+
+// parseDuration extends time.ParseDuration with a "d" suffix for whole days,
+// e.g. "2d".
+func parseDuration(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		var n int
+		if _, err := fmt.Sscanf(days, "%d", &n); err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	return d, nil
+}
+
+// formatDuration renders a duration at minute precision, e.g. "1d3h", "2h5m"
+// or "12m".
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd%dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh%dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm", minutes)
+	}
+}
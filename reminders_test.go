@@ -0,0 +1,55 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// TestRemindersResetOnActivity checks that acting on an overdue queue
+// starts its reminder count over, so it is not escalated for reminders that
+// were answered.
+func TestRemindersResetOnActivity(t *testing.T) {
+	tests := []struct {
+		name string
+		// setup runs before the reminders, as user U1
+		setup []string
+		user  string
+		text  string
+	}{
+		{"review", nil, "U2", "queue review 1"},
+		{"approve", nil, "U2", "queue approve 1"},
+		{"assign", nil, "U1", "queue assign 1 <@U1>"},
+		{"take", nil, "U3", "queue take 1"},
+		{"reassign", nil, "U1", "queue reassign 1 <@U2>"},
+		{"update", []string{"queue review 1"}, "U1", "queue update 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sh, _ := newTestHandler(t, nil)
+			command(sh, "U1", `queue add "New feature" https://example.com/mr/1 <@U2> <@U3>`)
+			for _, text := range tt.setup {
+				command(sh, "U1", text)
+			}
+			now := time.Now()
+			if _, err := sh.Store.Update(1, func(q *Queue) error {
+				q.SLADeadline = now.Add(-time.Hour)
+				return nil
+			}); err != nil {
+				t.Fatalf("Update: %v", err)
+			}
+
+			cfg := ReminderConfig{Interval: time.Minute, EscalateAfter: 2, EscalationTarget: "<@U9>"}
+			sh.remindOverdue(now, cfg)
+			sh.remindOverdue(now.Add(time.Minute), cfg)
+			if queue := mustGet(t, sh, 1); queue.ReminderCount != 2 || queue.LastEscalatedAt.IsZero() {
+				t.Fatalf("before %s: %d reminders, escalated at %v", tt.name, queue.ReminderCount, queue.LastEscalatedAt)
+			}
+
+			command(sh, tt.user, tt.text)
+			queue := mustGet(t, sh, 1)
+			if queue.ReminderCount != 0 || !queue.LastEscalatedAt.IsZero() {
+				t.Errorf("after %s: %d reminders, escalated at %v, want a fresh count", tt.name, queue.ReminderCount, queue.LastEscalatedAt)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+)
+
+// mentionPattern matches a resolved Slack user mention such as <@U123> or
+// <@U123|alice>.
+var mentionPattern = regexp.MustCompile(`^<@([UW][A-Z0-9]+)(\|[^>]*)?>$`)
+
+// parseMention extracts the user ID from a Slack user mention.
+func parseMention(token string) (string, bool) {
+	m := mentionPattern.FindStringSubmatch(token)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+// ReviewerPool is the persisted set of reviewers that tagless queues are
+// auto-assigned from.
+type ReviewerPool struct {
+	path    string
+	members []string
+	next    int
+	mu      sync.Mutex
+}
+
+// NewReviewerPool creates a new instance of ReviewerPool, loading any members
+// previously saved at path. An empty path keeps the pool in memory only.
+func NewReviewerPool(path string) (*ReviewerPool, error) {
+	rp := &ReviewerPool{path: path}
+	if path == "" {
+		return rp, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return rp, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read reviewer pool: %w", err)
+	}
+	if err := json.Unmarshal(data, &rp.members); err != nil {
+		return nil, fmt.Errorf("parse reviewer pool: %w", err)
+	}
+	return rp, nil
+}
+
+// Members returns a copy of the pool's user IDs.
+func (rp *ReviewerPool) Members() []string {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	return append([]string(nil), rp.members...)
+}
+
+// Add adds a user to the pool. It reports false if the user was already
+// present.
+func (rp *ReviewerPool) Add(userID string) (bool, error) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	for _, member := range rp.members {
+		if member == userID {
+			return false, nil
+		}
+	}
+	rp.members = append(rp.members, userID)
+	return true, rp.save()
+}
+
+// Remove removes a user from the pool. It reports false if the user was not
+// present.
+func (rp *ReviewerPool) Remove(userID string) (bool, error) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	for i, member := range rp.members {
+		if member == userID {
+			rp.members = append(rp.members[:i], rp.members[i+1:]...)
+			return true, rp.save()
+		}
+	}
+	return false, nil
+}
+
+// Next picks the next reviewer in round-robin order, skipping exclude. It
+// returns an empty string if no eligible reviewer exists.
+func (rp *ReviewerPool) Next(exclude string) string {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	for range rp.members {
+		member := rp.members[rp.next%len(rp.members)]
+		rp.next++
+		if member != exclude {
+			return member
+		}
+	}
+	return ""
+}
+
+// save writes the pool to disk. Callers must hold rp.mu.
+func (rp *ReviewerPool) save() error {
+	if rp.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(rp.members, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(rp.path, data)
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it
+// into place, so readers never observe a partially written file.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
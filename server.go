@@ -1,30 +1,92 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 // Server encapsulates the HTTP server configuration.
 type Server struct {
 	SlackHandler *SlackHandler
 	Port         string
+	// EventsPath is where Slack delivers Events API requests.
+	EventsPath string
 }
 
 // NewServer creates a new instance of Server.
-func NewServer(slackHandler *SlackHandler, port string) *Server {
+func NewServer(slackHandler *SlackHandler, port, eventsPath string) *Server {
 	return &Server{
 		SlackHandler: slackHandler,
 		Port:         port,
+		EventsPath:   eventsPath,
+	}
+}
+
+// reservedPath reports whether Handler serves path itself, so it can't be
+// used as EVENTS_PATH.
+func reservedPath(path string) bool {
+	switch path {
+	case "/interactions", "/commands", "/version", "/metrics", "/api/queues":
+		return true
 	}
+	return strings.HasPrefix(path, "/oauth/") || strings.HasPrefix(path, "/api/queues/")
 }
 
-// Start starts the HTTP server.
+// Handler returns a new mux with all of the server's routes, so several
+// servers can run in one process.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(s.EventsPath, s.SlackHandler.HandleEventEndpoint)
+	mux.HandleFunc("/interactions", s.SlackHandler.HandleInteractionEndpoint)
+	mux.HandleFunc("/commands", s.SlackHandler.HandleSlashCommand)
+	if s.SlackHandler.Teams != nil {
+		mux.HandleFunc("/oauth/install", s.SlackHandler.HandleOAuthInstall)
+		mux.HandleFunc("/oauth/callback", s.SlackHandler.HandleOAuthCallback)
+	}
+	if s.SlackHandler.APIKey != "" {
+		mux.HandleFunc("GET /api/queues", s.SlackHandler.HandleAPIQueues)
+		mux.HandleFunc("GET /api/queues/{id}", s.SlackHandler.HandleAPIQueue)
+	}
+	mux.HandleFunc("GET /version", HandleVersion)
+	mux.Handle("/metrics", promhttp.Handler())
+	return mux
+}
+
+// Start starts the HTTP server and blocks until SIGINT or SIGTERM, then
+// shuts it down gracefully.
 func (s *Server) Start() {
-	http.HandleFunc("/events-endpoint", s.SlackHandler.HandleEventEndpoint)
+	srv := &http.Server{
+		Addr:              fmt.Sprintf(":%s", s.Port),
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       10 * time.Second,
+		WriteTimeout:      10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		log.Printf("[INFO] Shutting down server")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Printf("[ERROR] Server shutdown failed: %v", err)
+		}
+	}()
+
 	log.Printf("[INFO] Server listening on port %s", s.Port)
-	if err := http.ListenAndServe(fmt.Sprintf(":%s", s.Port), nil); err != nil {
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Fatalf("[ERROR] Server failed: %v", err)
 	}
 }
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// signedRequest returns a POST to path carrying body, signed the way Slack
+// signs its requests.
+func signedRequest(path, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	req.Header.Set("X-Slack-Signature", slackSignature(testSigningSecret, timestamp, body))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+// slackSignature computes Slack's v0 request signature.
+func slackSignature(secret, timestamp, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":" + body))
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// messageEvent returns an event_callback envelope for a channel message.
+func messageEvent(user, text string) string {
+	return `{"type":"event_callback","team_id":"T1","event_id":"Ev1","event":` +
+		`{"type":"message","user":"` + user + `","channel":"C1","channel_type":"channel",` +
+		`"text":"` + text + `","ts":"1000.000001"}}`
+}
+
+// eventually polls cond until it holds or a second has passed, for work that
+// runs after the request was acknowledged.
+func eventually(t *testing.T, cond func() bool) bool {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return true
+}
+
+func TestSignedEventThroughMux(t *testing.T) {
+	sh, _ := newTestHandler(t, nil)
+	mux := NewServer(sh, "0", "/events-endpoint").Handler()
+
+	body := messageEvent("U1", `queue add \"New feature\" https://example.com/mr/1 <@U2>`)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, signedRequest("/events-endpoint", body))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	added := eventually(t, func() bool {
+		_, err := sh.Store.Get(1)
+		return err == nil
+	})
+	if !added {
+		t.Fatal("the signed event did not add a queue")
+	}
+	if queue := mustGet(t, sh, 1); queue.Title != "New feature" || queue.Owner != "U1" || queue.TeamID != "T1" {
+		t.Errorf("queue = %+v", queue)
+	}
+}
+
+func TestSignatureVerification(t *testing.T) {
+	body := messageEvent("U1", "queue list")
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	stale := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+
+	tests := []struct {
+		name      string
+		timestamp string
+		signature string
+		want      int
+	}{
+		{"valid", timestamp, slackSignature(testSigningSecret, timestamp, body), http.StatusOK},
+		{"wrong secret", timestamp, slackSignature("not-the-secret", timestamp, body), http.StatusUnauthorized},
+		{"tampered body", timestamp, slackSignature(testSigningSecret, timestamp, body+" "), http.StatusUnauthorized},
+		{"stale timestamp", stale, slackSignature(testSigningSecret, stale, body), http.StatusBadRequest},
+		{"missing signature", timestamp, "", http.StatusBadRequest},
+		{"missing timestamp", "", slackSignature(testSigningSecret, timestamp, body), http.StatusBadRequest},
+		{"missing headers", "", "", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sh, _ := newTestHandler(t, nil)
+			mux := NewServer(sh, "0", "/events-endpoint").Handler()
+
+			req := httptest.NewRequest(http.MethodPost, "/events-endpoint", strings.NewReader(body))
+			if tt.timestamp != "" {
+				req.Header.Set("X-Slack-Request-Timestamp", tt.timestamp)
+			}
+			if tt.signature != "" {
+				req.Header.Set("X-Slack-Signature", tt.signature)
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+// interaction returns the form body Slack posts for an interaction payload.
+func interaction(t *testing.T, callback slack.InteractionCallback) string {
+	t.Helper()
+	payload, err := json.Marshal(callback)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	return url.Values{"payload": {string(payload)}}.Encode()
+}
+
+func TestSlashCommandRepliesThroughResponseURL(t *testing.T) {
+	sh, api := newTestHandler(t, nil)
+	api.viewErr = errors.New("expired_trigger_id")
+	mux := NewServer(sh, "0", "/events-endpoint").Handler()
+
+	const responseURL = "https://hooks.slack.com/commands/T1/1/abc"
+	body := url.Values{
+		"command":      {slashQueueAdd},
+		"team_id":      {"T1"},
+		"user_id":      {"U1"},
+		"channel_id":   {"C1"},
+		"trigger_id":   {"1.2.3"},
+		"response_url": {responseURL},
+	}.Encode()
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, signedRequest("/commands", body))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	replied := eventually(t, func() bool {
+		return api.last().ResponseURL == responseURL
+	})
+	if got := api.last(); !replied || !strings.Contains(got.Text, "Couldn't open the form") {
+		t.Errorf("reply = %+v, want the open failure sent to the response URL", got)
+	}
+}
+
+// TestAddModalRepliesThroughResponseURL opens the add modal from a message
+// shortcut and submits it, checking that an error from the submission goes
+// to the shortcut's response URL.
+func TestAddModalRepliesThroughResponseURL(t *testing.T) {
+	sh, api := newTestHandler(t, nil)
+	mux := NewServer(sh, "0", "/events-endpoint").Handler()
+
+	const responseURL = "https://hooks.slack.com/actions/T1/1/abc"
+	shortcut := slack.InteractionCallback{
+		Type:        slack.InteractionTypeMessageAction,
+		CallbackID:  shortcutAddToQueue,
+		TriggerID:   "1.2.3",
+		ResponseURL: responseURL,
+		User:        slack.User{ID: "U1"},
+		Team:        slack.Team{ID: "T1"},
+		Channel:     slack.Channel{GroupConversation: slack.GroupConversation{Conversation: slack.Conversation{ID: "C1"}}},
+		Message:     slack.Message{Msg: slack.Msg{Text: "<https://example.com/mr/1>"}},
+	}
+	mux.ServeHTTP(httptest.NewRecorder(), signedRequest("/interactions", interaction(t, shortcut)))
+	if !eventually(t, func() bool { return len(api.openedViews()) == 1 }) {
+		t.Fatal("the shortcut did not open the add modal")
+	}
+	metadata := parseAddModalMetadata(api.openedViews()[0].PrivateMetadata)
+	if metadata != (addModalMetadata{Channel: "C1", ResponseURL: responseURL}) {
+		t.Fatalf("metadata = %+v", metadata)
+	}
+
+	// Without reviewers or a reviewer pool the add is rejected
+	submission := slack.InteractionCallback{
+		Type: slack.InteractionTypeViewSubmission,
+		User: slack.User{ID: "U1"},
+		Team: slack.Team{ID: "T1"},
+		View: slack.View{
+			CallbackID:      viewAddQueue,
+			PrivateMetadata: api.openedViews()[0].PrivateMetadata,
+			State: &slack.ViewState{Values: map[string]map[string]slack.BlockAction{
+				addBlockTitle: {addActionInput: {Value: "New feature"}},
+				addBlockLink:  {addActionInput: {Value: "https://example.com/mr/1"}},
+			}},
+		},
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, signedRequest("/interactions", interaction(t, submission)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	replied := eventually(t, func() bool {
+		return api.last().ResponseURL == responseURL
+	})
+	if got := api.last(); !replied || !strings.Contains(got.Text, "Tag at least one reviewer") {
+		t.Errorf("reply = %+v, want the error sent to the response URL", got)
+	}
+}
+
+func TestParseAddModalMetadata(t *testing.T) {
+	// Modals opened before the metadata held a response URL
+	if got := parseAddModalMetadata("C1"); got != (addModalMetadata{Channel: "C1"}) {
+		t.Errorf("legacy metadata = %+v, want channel C1", got)
+	}
+	got := parseAddModalMetadata(`{"channel":"C1","response_url":"https://hooks.slack.com/x"}`)
+	if got != (addModalMetadata{Channel: "C1", ResponseURL: "https://hooks.slack.com/x"}) {
+		t.Errorf("metadata = %+v", got)
+	}
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// Callback IDs of the "Add to review queue" message shortcut and the modal it
+// opens. The shortcut must be configured with this callback ID in the app
+// settings.
+const (
+	shortcutAddToQueue = "add_to_queue"
+	viewAddQueue       = "add_queue"
+)
+
+// Block and action IDs of the add modal's inputs.
+const (
+	addBlockTitle       = "title"
+	addBlockLink        = "link"
+	addBlockReviewers   = "reviewers"
+	addBlockDue         = "due"
+	addBlockDescription = "description"
+	addActionInput      = "input"
+)
+
+// sharedLink returns the first link in a message and the title of its unfurl,
+// if any.
+func sharedLink(msg slack.Message) (link, title string) {
+	for _, token := range slackTokenPattern.FindAllString(msg.Text, -1) {
+		if looksLikeURL(token) {
+			link = normalizeSlackLink(token)
+			break
+		}
+	}
+	for _, attachment := range msg.Attachments {
+		unfurled := attachment.FromURL
+		if unfurled == "" {
+			unfurled = attachment.TitleLink
+		}
+		if link == "" {
+			link = unfurled
+		}
+		if unfurled != "" && normalizeMRLink(unfurled) == normalizeMRLink(link) {
+			return link, attachment.Title
+		}
+	}
+	return link, ""
+}
+
+// normalizeSlackLink strips Slack's <...|label> wrapping from a link.
+func normalizeSlackLink(token string) string {
+	link := strings.TrimSuffix(strings.TrimPrefix(token, "<"), ">")
+	link, _, _ = strings.Cut(link, "|")
+	return link
+}
+
+// addModalMetadata is the add modal's private metadata. The modal carries
+// the response URL of the shortcut or slash command that opened it, so
+// replies to its submission can use it.
+type addModalMetadata struct {
+	Channel     string `json:"channel"`
+	ResponseURL string `json:"response_url,omitempty"`
+}
+
+// parseAddModalMetadata reads the add modal's private metadata. Modals
+// opened by earlier versions hold just the channel ID.
+func parseAddModalMetadata(metadata string) addModalMetadata {
+	var parsed addModalMetadata
+	if err := json.Unmarshal([]byte(metadata), &parsed); err != nil {
+		return addModalMetadata{Channel: metadata}
+	}
+	return parsed
+}
+
+// handleAddShortcut opens the add modal for the "Add to review queue" message
+// shortcut, pre-filled with the message's link.
+func (sh *SlackHandler) handleAddShortcut(callback slack.InteractionCallback) {
+	link, title := sharedLink(callback.Message)
+	sh.openAddModal(callback.TriggerID, callback.User.ID, callback.Channel.ID, link, title)
+}
+
+// openAddModal opens the modal for adding a queue to the channel, with the
+// link and title pre-filled if given.
+func (sh *SlackHandler) openAddModal(triggerID, userID, channel, link, title string) {
+	plainText := func(text string) *slack.TextBlockObject {
+		return slack.NewTextBlockObject(slack.PlainTextType, text, false, false)
+	}
+
+	titleInput := slack.NewPlainTextInputBlockElement(nil, addActionInput)
+	titleInput.InitialValue = title
+	linkInput := slack.NewPlainTextInputBlockElement(nil, addActionInput)
+	linkInput.InitialValue = link
+	reviewers := slack.NewOptionsMultiSelectBlockElement(slack.MultiOptTypeUser, nil, addActionInput)
+	due := slack.NewPlainTextInputBlockElement(plainText(t("shortcut.due_placeholder")), addActionInput)
+	description := slack.NewPlainTextInputBlockElement(nil, addActionInput)
+	description.Multiline = true
+
+	reviewersBlock := slack.NewInputBlock(addBlockReviewers, plainText(t("shortcut.reviewers")), nil, reviewers)
+	reviewersBlock.Optional = true
+	dueBlock := slack.NewInputBlock(addBlockDue, plainText(t("shortcut.due")), nil, due)
+	dueBlock.Optional = true
+	descriptionBlock := slack.NewInputBlock(addBlockDescription, plainText(t("shortcut.description")), nil, description)
+	descriptionBlock.Optional = true
+
+	metadata, err := json.Marshal(addModalMetadata{Channel: channel, ResponseURL: sh.responseURL})
+	if err != nil {
+		log.Printf("[ERROR] Failed to encode the add modal's metadata: %v", err)
+		return
+	}
+	view := slack.ModalViewRequest{
+		Type:            slack.VTModal,
+		CallbackID:      viewAddQueue,
+		Title:           plainText(t("shortcut.modal_title")),
+		Submit:          plainText(t("shortcut.submit")),
+		Close:           plainText(t("shortcut.cancel")),
+		PrivateMetadata: string(metadata),
+		Blocks: slack.Blocks{BlockSet: []slack.Block{
+			slack.NewInputBlock(addBlockTitle, plainText(t("shortcut.title")), nil, titleInput),
+			slack.NewInputBlock(addBlockLink, plainText(t("shortcut.link")), nil, linkInput),
+			reviewersBlock,
+			dueBlock,
+			descriptionBlock,
+		}},
+	}
+	if _, err := sh.API.OpenView(triggerID, view); err != nil {
+		log.Printf("[ERROR] Failed to open the add modal for %s: %v", userID, err)
+		sh.replyError(&slackevents.MessageEvent{User: userID, Channel: channel}, t("shortcut.open_failed"))
+	}
+}
+
+// addSubmission reads the add modal's inputs into a queue add command. On
+// invalid input it returns the errors to show, by block ID, instead.
+func (sh *SlackHandler) addSubmission(view slack.View, userID string) (string, map[string]string) {
+	value := func(blockID string) slack.BlockAction {
+		if view.State == nil {
+			return slack.BlockAction{}
+		}
+		return view.State.Values[blockID][addActionInput]
+	}
+	title := strings.TrimSpace(value(addBlockTitle).Value)
+	link := strings.TrimSpace(value(addBlockLink).Value)
+	due := strings.TrimSpace(value(addBlockDue).Value)
+	description := strings.TrimSpace(value(addBlockDescription).Value)
+
+	errs := make(map[string]string)
+	// Slack itself requires the title and link inputs
+	if err := sh.TextLimits.checkTitle(title); err != nil {
+		errs[addBlockTitle] = err.Error()
+	}
+	if !looksLikeURL(sh.MRLinks.expand(link)) || strings.ContainsFunc(link, unicode.IsSpace) {
+		errs[addBlockLink] = t("shortcut.invalid_link")
+	}
+	if due != "" {
+		if _, err := parseDue(due, time.Now().In(sh.Users.Location(userID))); err != nil {
+			errs[addBlockDue] = err.Error()
+		}
+	}
+	if err := sh.TextLimits.checkDescription(description); err != nil {
+		errs[addBlockDescription] = err.Error()
+	}
+	if len(errs) > 0 {
+		return "", errs
+	}
+
+	command := fmt.Sprintf("%s add %s %s", sh.CommandPrefix, quoteArg(title), link)
+	for _, userID := range value(addBlockReviewers).SelectedUsers {
+		command += fmt.Sprintf(" <@%s>", userID)
+	}
+	if due != "" {
+		command += " --due " + quoteArg(due)
+	}
+	if description != "" {
+		command += " --desc " + quoteArg(description)
+	}
+	return command, nil
+}
+
+// handleAddSubmission creates the queue described by a submitted add modal,
+// in the channel the modal was opened from.
+func (sh *SlackHandler) handleAddSubmission(w http.ResponseWriter, userID, command, channel string) {
+	sh.dispatchCommand(w, &slackevents.MessageEvent{
+		Type:    "message",
+		User:    userID,
+		Text:    command,
+		Channel: channel,
+	})
+}
+
+// quoteArg quotes text as a single command argument. Double quotes inside it,
+// straight or curly, would end the argument early, so they become single
+// quotes.
+func quoteArg(text string) string {
+	return `"` + strings.NewReplacer(`"`, "'", "“", "'", "”", "'").Replace(text) + `"`
+}
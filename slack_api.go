@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/slack-go/slack"
+)
+
+// SlackAPI is the subset of the Slack Web API the bot uses. *slack.Client
+// implements it; tests and alternative transports can supply their own.
+type SlackAPI interface {
+	AuthTest() (*slack.AuthTestResponse, error)
+	PostMessage(channelID string, options ...slack.MsgOption) (string, string, error)
+	PostEphemeral(channelID, userID string, options ...slack.MsgOption) (string, error)
+	UpdateMessage(channelID, timestamp string, options ...slack.MsgOption) (string, string, string, error)
+	PublishView(userID string, view slack.HomeTabViewRequest, hash string) (*slack.ViewResponse, error)
+	OpenView(triggerID string, view slack.ModalViewRequest) (*slack.ViewResponse, error)
+	GetConversationInfo(input *slack.GetConversationInfoInput) (*slack.Channel, error)
+	GetUserInfo(userID string) (*slack.User, error)
+	GetUserByEmail(email string) (*slack.User, error)
+	GetUsers(options ...slack.GetUsersOption) ([]slack.User, error)
+	AddPin(channel string, item slack.ItemRef) error
+	RemovePin(channel string, item slack.ItemRef) error
+}
+
+var _ SlackAPI = (*slack.Client)(nil)
+
+// Deadlines for Slack API calls. Listing users pages through the whole
+// workspace, so it gets longer.
+const (
+	slackTimeout      = 10 * time.Second
+	slackUsersTimeout = 2 * time.Minute
+)
+
+// NewSlackAPI returns the client for a bot token, with per-call deadlines and
+// failures counted in slackAPIErrors.
+func NewSlackAPI(token string) SlackAPI {
+	return NewMeteredAPI(timeoutAPI{slack.New(token)})
+}
+
+// slackContextAPI is the context-aware form of SlackAPI.
+type slackContextAPI interface {
+	AuthTestContext(ctx context.Context) (*slack.AuthTestResponse, error)
+	PostMessageContext(ctx context.Context, channelID string, options ...slack.MsgOption) (string, string, error)
+	PostEphemeralContext(ctx context.Context, channelID, userID string, options ...slack.MsgOption) (string, error)
+	UpdateMessageContext(ctx context.Context, channelID, timestamp string, options ...slack.MsgOption) (string, string, string, error)
+	PublishViewContext(ctx context.Context, userID string, view slack.HomeTabViewRequest, hash string) (*slack.ViewResponse, error)
+	OpenViewContext(ctx context.Context, triggerID string, view slack.ModalViewRequest) (*slack.ViewResponse, error)
+	GetConversationInfoContext(ctx context.Context, input *slack.GetConversationInfoInput) (*slack.Channel, error)
+	GetUserInfoContext(ctx context.Context, userID string) (*slack.User, error)
+	GetUserByEmailContext(ctx context.Context, email string) (*slack.User, error)
+	GetUsersContext(ctx context.Context, options ...slack.GetUsersOption) ([]slack.User, error)
+	AddPinContext(ctx context.Context, channel string, item slack.ItemRef) error
+	RemovePinContext(ctx context.Context, channel string, item slack.ItemRef) error
+}
+
+var _ slackContextAPI = (*slack.Client)(nil)
+
+// timeoutAPI gives every call a deadline, so a slow Slack can't hang a
+// handler, or hold sh.mu for calls made under it. Handlers run after the
+// request has been acknowledged, so each call gets a fresh context rather
+// than the request's.
+type timeoutAPI struct {
+	client slackContextAPI
+}
+
+func (ta timeoutAPI) AuthTest() (*slack.AuthTestResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), slackTimeout)
+	defer cancel()
+	return ta.client.AuthTestContext(ctx)
+}
+
+func (ta timeoutAPI) PostMessage(channelID string, options ...slack.MsgOption) (string, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), slackTimeout)
+	defer cancel()
+	return ta.client.PostMessageContext(ctx, channelID, options...)
+}
+
+func (ta timeoutAPI) PostEphemeral(channelID, userID string, options ...slack.MsgOption) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), slackTimeout)
+	defer cancel()
+	return ta.client.PostEphemeralContext(ctx, channelID, userID, options...)
+}
+
+func (ta timeoutAPI) UpdateMessage(channelID, timestamp string, options ...slack.MsgOption) (string, string, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), slackTimeout)
+	defer cancel()
+	return ta.client.UpdateMessageContext(ctx, channelID, timestamp, options...)
+}
+
+func (ta timeoutAPI) PublishView(userID string, view slack.HomeTabViewRequest, hash string) (*slack.ViewResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), slackTimeout)
+	defer cancel()
+	return ta.client.PublishViewContext(ctx, userID, view, hash)
+}
+
+func (ta timeoutAPI) OpenView(triggerID string, view slack.ModalViewRequest) (*slack.ViewResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), slackTimeout)
+	defer cancel()
+	return ta.client.OpenViewContext(ctx, triggerID, view)
+}
+
+func (ta timeoutAPI) GetConversationInfo(input *slack.GetConversationInfoInput) (*slack.Channel, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), slackTimeout)
+	defer cancel()
+	return ta.client.GetConversationInfoContext(ctx, input)
+}
+
+func (ta timeoutAPI) GetUserInfo(userID string) (*slack.User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), slackTimeout)
+	defer cancel()
+	return ta.client.GetUserInfoContext(ctx, userID)
+}
+
+func (ta timeoutAPI) GetUserByEmail(email string) (*slack.User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), slackTimeout)
+	defer cancel()
+	return ta.client.GetUserByEmailContext(ctx, email)
+}
+
+func (ta timeoutAPI) GetUsers(options ...slack.GetUsersOption) ([]slack.User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), slackUsersTimeout)
+	defer cancel()
+	return ta.client.GetUsersContext(ctx, options...)
+}
+
+func (ta timeoutAPI) AddPin(channel string, item slack.ItemRef) error {
+	ctx, cancel := context.WithTimeout(context.Background(), slackTimeout)
+	defer cancel()
+	return ta.client.AddPinContext(ctx, channel, item)
+}
+
+func (ta timeoutAPI) RemovePin(channel string, item slack.ItemRef) error {
+	ctx, cancel := context.WithTimeout(context.Background(), slackTimeout)
+	defer cancel()
+	return ta.client.RemovePinContext(ctx, channel, item)
+}
+
+// slackAPIErrors counts failed Slack API calls by method. It is shared by the
+// clients of every workspace and registered by RegisterMetrics.
+var slackAPIErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "review_queue_slack_api_errors_total",
+	Help: "Slack API calls that returned an error, by method.",
+}, []string{"method"})
+
+// meteredAPI wraps a SlackAPI, counting and logging every failed call so
+// errors the callers discard are still visible.
+type meteredAPI struct {
+	SlackAPI
+}
+
+// NewMeteredAPI wraps api so its failures are counted in slackAPIErrors.
+func NewMeteredAPI(api SlackAPI) SlackAPI {
+	return meteredAPI{api}
+}
+
+func (m meteredAPI) record(method, target string, err error) {
+	if err == nil {
+		return
+	}
+	slackAPIErrors.WithLabelValues(method).Inc()
+	if target == "" {
+		log.Printf("[WARN] Slack API %s failed: %v", method, err)
+		return
+	}
+	log.Printf("[WARN] Slack API %s failed for %s: %v", method, target, err)
+}
+
+func (m meteredAPI) AuthTest() (*slack.AuthTestResponse, error) {
+	resp, err := m.SlackAPI.AuthTest()
+	m.record("auth.test", "", err)
+	return resp, err
+}
+
+func (m meteredAPI) PostMessage(channelID string, options ...slack.MsgOption) (string, string, error) {
+	channel, ts, err := m.SlackAPI.PostMessage(channelID, options...)
+	m.record("chat.postMessage", channelID, err)
+	return channel, ts, err
+}
+
+func (m meteredAPI) PostEphemeral(channelID, userID string, options ...slack.MsgOption) (string, error) {
+	ts, err := m.SlackAPI.PostEphemeral(channelID, userID, options...)
+	m.record("chat.postEphemeral", channelID+"/"+userID, err)
+	return ts, err
+}
+
+func (m meteredAPI) UpdateMessage(channelID, timestamp string, options ...slack.MsgOption) (string, string, string, error) {
+	channel, ts, text, err := m.SlackAPI.UpdateMessage(channelID, timestamp, options...)
+	m.record("chat.update", channelID, err)
+	return channel, ts, text, err
+}
+
+func (m meteredAPI) PublishView(userID string, view slack.HomeTabViewRequest, hash string) (*slack.ViewResponse, error) {
+	resp, err := m.SlackAPI.PublishView(userID, view, hash)
+	m.record("views.publish", userID, err)
+	return resp, err
+}
+
+func (m meteredAPI) OpenView(triggerID string, view slack.ModalViewRequest) (*slack.ViewResponse, error) {
+	resp, err := m.SlackAPI.OpenView(triggerID, view)
+	m.record("views.open", "", err)
+	return resp, err
+}
+
+func (m meteredAPI) GetConversationInfo(input *slack.GetConversationInfoInput) (*slack.Channel, error) {
+	channel, err := m.SlackAPI.GetConversationInfo(input)
+	m.record("conversations.info", input.ChannelID, err)
+	return channel, err
+}
+
+func (m meteredAPI) GetUserInfo(userID string) (*slack.User, error) {
+	user, err := m.SlackAPI.GetUserInfo(userID)
+	m.record("users.info", userID, err)
+	return user, err
+}
+
+func (m meteredAPI) GetUserByEmail(email string) (*slack.User, error) {
+	user, err := m.SlackAPI.GetUserByEmail(email)
+	m.record("users.lookupByEmail", "", err)
+	return user, err
+}
+
+func (m meteredAPI) GetUsers(options ...slack.GetUsersOption) ([]slack.User, error) {
+	users, err := m.SlackAPI.GetUsers(options...)
+	m.record("users.list", "", err)
+	return users, err
+}
+
+func (m meteredAPI) AddPin(channel string, item slack.ItemRef) error {
+	err := m.SlackAPI.AddPin(channel, item)
+	m.record("pins.add", channel, err)
+	return err
+}
+
+func (m meteredAPI) RemovePin(channel string, item slack.ItemRef) error {
+	err := m.SlackAPI.RemovePin(channel, item)
+	m.record("pins.remove", channel, err)
+	return err
+}
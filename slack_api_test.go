@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/slack-go/slack"
+)
+
+// fakeAPI is a SlackAPI that records what the bot sends instead of calling
+// Slack. Handlers run concurrently, so it is safe for concurrent use.
+type fakeAPI struct {
+	// authErr, if set, is returned by AuthTest.
+	authErr error
+	// viewErr, if set, is returned by OpenView.
+	viewErr error
+	// locked, if set, reports whether the handler lock is held. Messages
+	// sent while it is are counted in lockedPosts.
+	locked func() bool
+
+	mu          sync.Mutex
+	messages    []fakeMessage
+	views       []slack.ModalViewRequest
+	ts          int
+	lockedPosts []string
+}
+
+// fakeMessage is a message sent through fakeAPI. User is only set for
+// ephemeral messages, ResponseURL for replies to a response URL.
+type fakeMessage struct {
+	Channel     string
+	User        string
+	Text        string
+	ResponseURL string
+}
+
+var _ SlackAPI = (*fakeAPI)(nil)
+
+// record saves the message and returns a new timestamp for it.
+func (f *fakeAPI) record(channel, user string, options []slack.MsgOption) string {
+	endpoint, values, _ := slack.UnsafeApplyMsgOptions("", channel, "", options...)
+	var responseURL string
+	if strings.HasPrefix(endpoint, "https://") {
+		responseURL = endpoint
+	}
+	locked := f.locked != nil && f.locked()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.messages = append(f.messages, fakeMessage{Channel: channel, User: user, Text: values.Get("text"), ResponseURL: responseURL})
+	if locked {
+		f.lockedPosts = append(f.lockedPosts, values.Get("text"))
+	}
+	f.ts++
+	return fmt.Sprintf("1000.%06d", f.ts)
+}
+
+// sent returns the messages sent so far, oldest first.
+func (f *fakeAPI) sent() []fakeMessage {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]fakeMessage(nil), f.messages...)
+}
+
+// reset forgets the messages sent so far.
+func (f *fakeAPI) reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.messages = nil
+}
+
+// last returns the most recent message, or a zero message if none was sent.
+func (f *fakeAPI) last() fakeMessage {
+	sent := f.sent()
+	if len(sent) == 0 {
+		return fakeMessage{}
+	}
+	return sent[len(sent)-1]
+}
+
+func (f *fakeAPI) AuthTest() (*slack.AuthTestResponse, error) {
+	if f.authErr != nil {
+		return nil, f.authErr
+	}
+	return &slack.AuthTestResponse{UserID: "UBOT", User: "queuebot", TeamID: "T1", Team: "test"}, nil
+}
+
+func (f *fakeAPI) PostMessage(channelID string, options ...slack.MsgOption) (string, string, error) {
+	return channelID, f.record(channelID, "", options), nil
+}
+
+func (f *fakeAPI) PostEphemeral(channelID, userID string, options ...slack.MsgOption) (string, error) {
+	return f.record(channelID, userID, options), nil
+}
+
+func (f *fakeAPI) UpdateMessage(channelID, timestamp string, options ...slack.MsgOption) (string, string, string, error) {
+	return channelID, timestamp, "", nil
+}
+
+func (f *fakeAPI) PublishView(userID string, view slack.HomeTabViewRequest, hash string) (*slack.ViewResponse, error) {
+	return &slack.ViewResponse{}, nil
+}
+
+func (f *fakeAPI) OpenView(triggerID string, view slack.ModalViewRequest) (*slack.ViewResponse, error) {
+	if f.viewErr != nil {
+		return nil, f.viewErr
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.views = append(f.views, view)
+	return &slack.ViewResponse{}, nil
+}
+
+// openedViews returns the modals opened so far, oldest first.
+func (f *fakeAPI) openedViews() []slack.ModalViewRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]slack.ModalViewRequest(nil), f.views...)
+}
+
+func (f *fakeAPI) GetConversationInfo(input *slack.GetConversationInfoInput) (*slack.Channel, error) {
+	channel := &slack.Channel{}
+	channel.ID = input.ChannelID
+	channel.Name = "general"
+	channel.IsMember = true
+	return channel, nil
+}
+
+func (f *fakeAPI) GetUserInfo(userID string) (*slack.User, error) {
+	return &slack.User{ID: userID, Name: userID, TZ: "UTC"}, nil
+}
+
+func (f *fakeAPI) GetUserByEmail(email string) (*slack.User, error) {
+	return nil, fmt.Errorf("users_not_found")
+}
+
+func (f *fakeAPI) GetUsers(options ...slack.GetUsersOption) ([]slack.User, error) {
+	return []slack.User{{ID: "U1", Name: "alice"}, {ID: "U2", Name: "bob"}, {ID: "U3", Name: "carol"}}, nil
+}
+
+func (f *fakeAPI) AddPin(channel string, item slack.ItemRef) error {
+	return nil
+}
+
+func (f *fakeAPI) RemovePin(channel string, item slack.ItemRef) error {
+	return nil
+}
@@ -2,332 +2,1413 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
-	"strconv"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/slack-go/slack"
 	"github.com/slack-go/slack/slackevents"
 )
 
 type Queue struct {
-	ID            int
-	Title         string
-	MRLink        string
-	Tags          []string
-	Owner         string
-	InReviewState bool
+	ID            int      `json:"id"`
+	Title         string   `json:"title"`
+	MRLink        string   `json:"mr_link"`
+	Description   string   `json:"description,omitempty"`
+	Key           string   `json:"key,omitempty"`
+	Tags          []string `json:"tags"`
+	Approvers     []string `json:"approvers,omitempty"` // tags that have approved, in order
+	Labels        []string `json:"labels,omitempty"`
+	Size          string   `json:"size,omitempty"` // S, M, L, XL or a line count
+	Owner         string   `json:"owner"`
+	InReviewState bool     `json:"in_review"`
+	Reviewer      string   `json:"reviewer,omitempty"`
+	Channel       string   `json:"channel"`
+	TeamID        string   `json:"team_id,omitempty"`
+	PinnedChannel string   `json:"pinned_channel,omitempty"` // pinned "now in review" message
+	PinnedTS      string   `json:"pinned_ts,omitempty"`
+	StatusChannel string   `json:"status_channel,omitempty"` // "added" message and its status card thread
+	StatusTS      string   `json:"status_ts,omitempty"`
+	ThreadTS      string   `json:"thread_ts,omitempty"`
+
+	// SourceThreadTS is the thread the queue was added in, if any.
+	SourceThreadTS string `json:"source_thread_ts,omitempty"`
+
+	// Checklist holds review items that must all be checked before the
+	// queue can be fully approved, when checklists are enforced.
+	Checklist []ChecklistItem `json:"checklist,omitempty"`
+
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at,omitempty"`
+	SLADeadline    time.Time `json:"sla_deadline,omitempty"`
+	LastRemindedAt time.Time `json:"last_reminded_at,omitempty"`
+	LastPingedAt   time.Time `json:"last_pinged_at,omitempty"`
+	SnoozedUntil   time.Time `json:"snoozed_until,omitempty"` // reminders paused until then
+	ApprovedAt     time.Time `json:"approved_at,omitempty"`
+	ArchivedAt     time.Time `json:"archived_at,omitempty"`
+
+	ReminderCount   int       `json:"reminder_count,omitempty"`
+	LastEscalatedAt time.Time `json:"last_escalated_at,omitempty"`
+
+	// ReviewTime sums the finished intervals the queue spent in review, and
+	// ReviewStartedAt is when the current one began; read them through
+	// timeInReview().
+	ReviewTime      time.Duration `json:"review_time,omitempty"`
+	ReviewStartedAt time.Time     `json:"review_started_at,omitempty"`
+
+	// RequiredApprovals is the approval count that completes the queue in
+	// ApproveModeCount, or zero in ApproveModeTag.
+	RequiredApprovals int `json:"required_approvals,omitempty"`
+
+	// Status is the lifecycle state, empty for queues saved before it was
+	// tracked; read it through status().
+	Status QueueStatus `json:"status,omitempty"`
 }
 
 type SlackHandler struct {
-	API           *slack.Client
+	API           SlackAPI
 	SigningSecret string
-	Queues        map[int]*Queue
-	NextID        int
-	mu            sync.Mutex
-	BotUserID     string
+	Store         Store
+	// mu serializes the handlers' read-modify-write sequences on the store,
+	// such as a findQueue followed by an Update or a new ID followed by a
+	// Create, and guards homeViewers, undo and the ID sequences. Events run
+	// concurrently, each in its own goroutine, so any code that reads queues
+	// to decide a change must hold it until the change is saved. Snapshot
+	// what replies need while holding it and call Slack after unlocking.
+	// It is shared with the handlers of other teams.
+	mu        *sync.Mutex
+	TeamID    string
+	BotUserID string
+	// responseURL, if set, receives the private replies to the slash command
+	// or interaction being handled. See withResponseURL.
+	responseURL string
+	// homeTeam is the workspace of SLACK_BOT_TOKEN, which owns queues saved
+	// without a team and whose admins may be listed without one.
+	homeTeam string
+	// Admins holds the ADMIN_USERS entries: user IDs for the home workspace
+	// and TEAM:USER pairs for the others.
+	Admins map[string]bool
+	// AllowedChannels limits commands to these channels; empty allows all.
+	AllowedChannels map[string]bool
+	// MaxBodyBytes caps the size of incoming Slack requests.
+	MaxBodyBytes int64
+	// ConfirmRemove asks for confirmation before removing queues.
+	ConfirmRemove bool
+	// PinReviews pins the "now in review" message while a queue is in review.
+	PinReviews bool
+	// StatusCards keeps a status card in a thread under each new queue.
+	StatusCards bool
+	// ReprocessEdits runs an edited message again as a new command.
+	ReprocessEdits bool
+	// AllowSelfApprove lets owners approve their own queues.
+	AllowSelfApprove bool
+	// ThreadScoped makes commands run in a thread act on that thread's queues.
+	ThreadScoped bool
+	// RequireChecklist blocks the approval that would complete a queue
+	// while its checklist has unchecked items.
+	RequireChecklist bool
+	// AutoArchive archives queues as soon as they are fully approved. With
+	// AUTOARCHIVE_AFTER set, StartAutoArchive archives them later instead.
+	AutoArchive bool
+	// CommandPrefix is the word that triggers the bot, "queue" by default.
+	CommandPrefix string
+	Capacity      CapacityConfig
+	MRLinks       MRLinkConfig
+	TextLimits    TextLimits
+	Reactions     ReactionConfig
+	IDs           *QueueIDs
+	Reviewers     *ReviewerPool
+	// Channels holds per-channel settings such as the approve mode.
+	Channels *ChannelConfigs
+	Users    *UserDirectory
+	Audit    *AuditLog
+	Webhook  *WebhookNotifier
+	// DMs batches direct messages to users; it is shared by every team.
+	DMs      *DMBatcher
+	commands []Command
+	// homeViewers tracks users who have opened the App Home tab, so their
+	// view can be refreshed when their queues change.
+	homeViewers map[string]bool
+	// undo holds recently removed queues per channel for `queue undo`.
+	undo map[string][]undoEntry
+
+	// APIKey guards the read-only HTTP API, which is off when it is empty.
+	APIKey string
+
+	// OAuth and Teams serve workspaces that installed the bot through OAuth;
+	// Teams is nil in single-token mode.
+	OAuth OAuthConfig
+	Teams *TeamTokens
+	// teams caches the handler of each team, see forTeam.
+	teams *sync.Map
 }
 
-func NewSlackHandler(botToken, signingSecret string) *SlackHandler {
-	client := slack.New(botToken)
-	authResp, err := client.AuthTest()
+// NewSlackHandler creates a new instance of SlackHandler. It fails if the bot
+// token can't be verified, since without the bot's user ID the handler would
+// respond to its own messages.
+func NewSlackHandler(cfg *Config, api SlackAPI, store Store, reviewers *ReviewerPool, channels *ChannelConfigs, teams *TeamTokens, webhook *WebhookNotifier) (*SlackHandler, error) {
+	// authResp is only valid when err is nil
+	authResp, err := api.AuthTest()
 	if err != nil {
-		log.Printf("[ERROR] Failed to authenticate bot: %v", err)
+		return nil, fmt.Errorf("authenticate bot: %w", err)
+	}
+	log.Printf("[INFO] Authenticated as bot user %s (%s) in team %s", authResp.UserID, authResp.User, authResp.Team)
+
+	adminSet := make(map[string]bool, len(cfg.Admins))
+	for _, admin := range cfg.Admins {
+		adminSet[admin] = true
 	}
 
-	return &SlackHandler{
-		API:           client,
-		SigningSecret: signingSecret,
-		Queues:        make(map[int]*Queue),
-		NextID:        1,
+	var allowed map[string]bool
+	if len(cfg.AllowedChannels) > 0 {
+		allowed = make(map[string]bool, len(cfg.AllowedChannels))
+		for _, channel := range cfg.AllowedChannels {
+			allowed[channel] = true
+		}
+	}
+
+	sh := &SlackHandler{
+		API:           api,
+		SigningSecret: cfg.SigningSecret,
+		Store:         store,
+		mu:            new(sync.Mutex),
+		TeamID:        authResp.TeamID,
+		homeTeam:      authResp.TeamID,
 		BotUserID:     authResp.UserID,
+		Admins:        adminSet,
+		MaxBodyBytes:  int64(cfg.MaxBodyBytes),
+		ConfirmRemove: cfg.ConfirmRemove,
+		CommandPrefix: cfg.CommandPrefix,
+		APIKey:        cfg.APIKey,
+		PinReviews:    cfg.PinReviews,
+		StatusCards:   cfg.StatusCards,
+		Capacity:      cfg.Capacity,
+		MRLinks:       cfg.MRLinks,
+		TextLimits:    cfg.TextLimits,
+		Reactions:     cfg.Reactions,
+		IDs:           NewQueueIDs(api, cfg.IDFormat, cfg.IDPrefixes),
+		Reviewers:     reviewers,
+		Channels:      channels,
+		Users:         NewUserDirectory(api),
+		Audit:         NewAuditLog(),
+		DMs:           NewDMBatcher(cfg.NotifyBatchWindow),
+		Webhook:       webhook,
+		commands:      defaultCommands(),
+		homeViewers:   make(map[string]bool),
+		undo:          make(map[string][]undoEntry),
+		OAuth:         cfg.OAuth,
+		Teams:         teams,
+		teams:         new(sync.Map),
+
+		ReprocessEdits:   cfg.ReprocessEdits,
+		AllowSelfApprove: cfg.AllowSelfApprove,
+		ThreadScoped:     cfg.ThreadScoped,
+		AutoArchive:      cfg.AutoArchive && cfg.AutoArchiveAfter <= 0,
+		RequireChecklist: cfg.RequireChecklist,
+		AllowedChannels:  allowed,
+	}
+	sh.teams.Store(sh.TeamID, sh)
+
+	if cfg.Snapshot.Path != "" {
+		if err := sh.loadSnapshot(cfg.Snapshot.Path); err != nil {
+			log.Printf("[ERROR] Failed to load queue snapshot: %v", err)
+		}
+	}
+	return sh, nil
+}
+
+// isAdmin reports whether the user is listed in ADMIN_USERS for this
+// handler's workspace.
+func (sh *SlackHandler) isAdmin(userID string) bool {
+	if sh.Admins[sh.TeamID+":"+userID] {
+		return true
 	}
+	return sh.TeamID == sh.homeTeam && sh.Admins[userID]
 }
 
 func (sh *SlackHandler) HandleEventEndpoint(w http.ResponseWriter, r *http.Request) {
-	body, err := io.ReadAll(r.Body)
+	body, ok := sh.readVerifiedBody(w, r)
+	if !ok {
+		return
+	}
+
+	eventsAPIEvent, err := slackevents.ParseEvent(json.RawMessage(body), slackevents.OptionNoVerifyToken())
 	if err != nil {
-		log.Printf("[ERROR] Failed to read request body: %v", err)
+		log.Printf("[ERROR] Failed to parse Slack event: %v", err)
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
 
+	switch eventsAPIEvent.Type {
+	case slackevents.URLVerification:
+		sh.handleURLVerification(w, body)
+	case slackevents.CallbackEvent:
+		// ackAndRun writes the 200 before dispatching
+		sh.ackAndRun(w, func(w http.ResponseWriter) {
+			sh.forTeam(eventsAPIEvent.TeamID).handleCallbackEvent(w, eventsAPIEvent)
+		})
+	default:
+		// Acknowledge anyway; an error status would only make Slack retry
+		log.Printf("[WARN] Unsupported event type: %s", eventsAPIEvent.Type)
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// readVerifiedBody reads the request body and checks its Slack signature. On
+// failure it writes the error status and returns false.
+func (sh *SlackHandler) readVerifiedBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, sh.MaxBodyBytes))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		log.Printf("[WARN] Request body exceeds %d bytes", tooLarge.Limit)
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		return nil, false
+	}
+	if err != nil {
+		log.Printf("[ERROR] Failed to read request body: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return nil, false
+	}
+
 	sv, err := slack.NewSecretsVerifier(r.Header, sh.SigningSecret)
 	if err != nil {
 		log.Printf("[ERROR] Failed to create secrets verifier: %v", err)
 		w.WriteHeader(http.StatusBadRequest)
-		return
+		return nil, false
 	}
 	if _, err := sv.Write(body); err != nil {
 		log.Printf("[ERROR] Failed to write to secrets verifier: %v", err)
 		w.WriteHeader(http.StatusInternalServerError)
-		return
+		return nil, false
 	}
 	if err := sv.Ensure(); err != nil {
 		log.Printf("[ERROR] Secret verification failed: %v", err)
 		w.WriteHeader(http.StatusUnauthorized)
-		return
-	}
-
-	eventsAPIEvent, err := slackevents.ParseEvent(json.RawMessage(body), slackevents.OptionNoVerifyToken())
-	if err != nil {
-		log.Printf("[ERROR] Failed to parse Slack event: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		return
-	}
-
-	switch eventsAPIEvent.Type {
-	case slackevents.URLVerification:
-		sh.handleURLVerification(w, body)
-	case slackevents.CallbackEvent:
-		sh.handleCallbackEvent(w, eventsAPIEvent.InnerEvent)
-	default:
-		log.Printf("[WARN] Unsupported event type: %s", eventsAPIEvent.Type)
-		w.WriteHeader(http.StatusNotImplemented)
+		return nil, false
 	}
+	return body, true
 }
 
 func (sh *SlackHandler) handleURLVerification(w http.ResponseWriter, body []byte) {
 	var challengeResponse *slackevents.ChallengeResponse
 	if err := json.Unmarshal(body, &challengeResponse); err != nil {
 		log.Printf("[ERROR] Failed to unmarshal challenge response: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
-	w.Header().Set("Content-Type", "text")
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
 	w.Write([]byte(challengeResponse.Challenge))
 }
 
-func (sh *SlackHandler) handleCallbackEvent(w http.ResponseWriter, innerEvent slackevents.EventsAPIInnerEvent) {
-	switch ev := innerEvent.Data.(type) {
+// callbackEventID returns the envelope's event ID, which stays the same when
+// Slack retries a delivery.
+func callbackEventID(event slackevents.EventsAPIEvent) string {
+	if callback, ok := event.Data.(*slackevents.EventsAPICallbackEvent); ok {
+		return callback.EventID
+	}
+	return ""
+}
+
+// handleCallbackEvent receives the whole envelope rather than just the inner
+// event, so the team and event IDs are available alongside it.
+func (sh *SlackHandler) handleCallbackEvent(w http.ResponseWriter, event slackevents.EventsAPIEvent) {
+	// Without a bot user ID, only subtypes identify the bot's own messages
+	botUserID := sh.botUserID()
+	switch ev := event.InnerEvent.Data.(type) {
 	case *slackevents.MessageEvent:
-		if ev.User == sh.BotUserID || ev.SubType != "" {
+		switch ev.SubType {
+		case "":
+		case "message_changed":
+			if ev = sh.editedCommand(ev); ev == nil {
+				return
+			}
+		case "message_deleted":
+			// Deleting a command doesn't undo it
 			return
-		}
-		command := strings.TrimSpace(ev.Text)
-		switch {
-		case strings.HasPrefix(command, "queue add"):
-			sh.handleQueueAdd(w, ev)
-		case strings.HasPrefix(command, "queue list"):
-			sh.handleQueueList(w, ev)
-		case strings.HasPrefix(command, "queue remove"):
-			sh.handleQueueRemove(w, ev)
-		case strings.HasPrefix(command, "queue approve"):
-			sh.handleQueueApprove(w, ev)
-		case strings.HasPrefix(command, "queue review"):
-			sh.handleQueueReview(w, ev)
-		case strings.HasPrefix(command, "queue update"):
-			sh.handleQueueUpdate(w, ev)
-		case strings.HasPrefix(command, "queue help"):
-			sh.handleQueueHelp(w, ev)
 		default:
-			log.Printf("[INFO] Unrecognized command: %s", command)
+			return
+		}
+		if botUserID != "" && ev.User == botUserID {
+			return
+		}
+		if isDirectMessage(ev) {
+			// Slack sends no app_mention in DMs, and every DM is addressed
+			// to the bot anyway
+			ev.Text = sh.stripBotMention(ev.Text)
+		} else if sh.Channels.Settings(ev.Channel).RequireMention || isBotMention(ev.Text, botUserID) {
+			// Mentions arrive again as app_mention events; handle them there only
+			return
 		}
+		sh.dispatchCommand(w, ev)
+	case *slackevents.AppHomeOpenedEvent:
+		sh.handleAppHomeOpened(ev)
+	case *slackevents.ReactionAddedEvent:
+		if botUserID != "" && ev.User == botUserID {
+			return
+		}
+		sh.handleReactionAdded(ev)
+	case *slackevents.ReactionRemovedEvent:
+		sh.handleReactionRemoved(ev)
+	case *slackevents.AppMentionEvent:
+		if botUserID != "" && ev.User == botUserID {
+			return
+		}
+		sh.dispatchCommand(w, &slackevents.MessageEvent{
+			Type:            "message",
+			User:            ev.User,
+			Text:            sh.stripBotMention(ev.Text),
+			TimeStamp:       ev.TimeStamp,
+			ThreadTimeStamp: ev.ThreadTimeStamp,
+			Channel:         ev.Channel,
+			EventTimeStamp:  ev.EventTimeStamp,
+		})
 	default:
-		log.Printf("[WARN] Unsupported inner event type: %T", innerEvent.Data)
+		log.Printf("[WARN] Unsupported inner event type %T (event %s, team %s)", event.InnerEvent.Data, callbackEventID(event), event.TeamID)
+	}
+}
+
+// editedCommand returns the new version of an edited message to run as a
+// command, or nil if edits aren't reprocessed. Slack also sends
+// message_changed when it unfurls a link, so an unchanged text is skipped to
+// avoid running the same command twice.
+func (sh *SlackHandler) editedCommand(ev *slackevents.MessageEvent) *slackevents.MessageEvent {
+	edited := ev.Message
+	if !sh.ReprocessEdits || edited == nil || edited.SubType != "" || edited.BotID != "" {
+		return nil
+	}
+	if ev.PreviousMessage != nil && ev.PreviousMessage.Text == edited.Text {
+		return nil
+	}
+	return &slackevents.MessageEvent{
+		Type:            "message",
+		User:            edited.User,
+		Text:            edited.Text,
+		TimeStamp:       edited.TimeStamp,
+		ThreadTimeStamp: edited.ThreadTimeStamp,
+		Channel:         ev.Channel,
+		ChannelType:     ev.ChannelType,
+		EventTimeStamp:  ev.EventTimeStamp,
+	}
+}
+
+// dispatchCommand runs the registered command invoked by the message.
+func (sh *SlackHandler) dispatchCommand(w http.ResponseWriter, ev *slackevents.MessageEvent) {
+	text := strings.TrimSpace(ev.Text)
+	if !sh.channelAllowed(ev) {
+		log.Printf("[INFO] Ignoring command in channel %s, which is not in ALLOWED_CHANNELS", ev.Channel)
+		if _, ok := matchCommand(text, sh.CommandPrefix); ok {
+			sh.replyError(ev, t("cmd.channel_not_allowed"))
+		}
+		return
+	}
+	cmd, command := sh.lookupCommand(text)
+	if cmd == nil {
+		log.Printf("[INFO] Unrecognized command: %s", command)
+		// Stay quiet about ordinary chatter
+		if _, ok := matchCommand(text, sh.CommandPrefix); ok {
+			if suggestion := sh.suggestCommand(command); suggestion != "" {
+				sh.replyError(ev, t("cmd.did_you_mean", suggestion))
+			} else {
+				sh.replyError(ev, t("cmd.unknown"))
+			}
+		}
+		return
 	}
+	ev.Text = sh.withThreadQueue(cmd, command, ev)
+	cmd.Handler(sh, w, ev)
+}
+
+// channelAllowed reports whether commands may run where the message was sent.
+// DMs are always allowed, since only the sender sees them.
+func (sh *SlackHandler) channelAllowed(ev *slackevents.MessageEvent) bool {
+	return len(sh.AllowedChannels) == 0 || sh.AllowedChannels[ev.Channel] || isDirectMessage(ev)
+}
+
+// isDirectMessage reports whether the message was sent in a DM with the bot.
+// Messages rebuilt from edits and buttons carry no channel type, so the
+// conversation ID's D prefix is checked too.
+func isDirectMessage(ev *slackevents.MessageEvent) bool {
+	return ev.ChannelType == "im" || strings.HasPrefix(ev.Channel, "D")
+}
+
+// isBotMention reports whether the text starts by mentioning the bot.
+func isBotMention(text, botUserID string) bool {
+	return botUserID != "" && strings.HasPrefix(strings.TrimSpace(text), "<@"+botUserID)
+}
+
+// stripBotMention removes a leading bot mention, e.g. "<@UBOT> queue list"
+// becomes "queue list".
+func (sh *SlackHandler) stripBotMention(text string) string {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "<@") {
+		return text
+	}
+	if end := strings.Index(text, ">"); end != -1 {
+		return strings.TrimSpace(text[end+1:])
+	}
+	return text
 }
 
 func (sh *SlackHandler) handleQueueAdd(w http.ResponseWriter, ev *slackevents.MessageEvent) {
-	parts := strings.Fields(ev.Text)
+	parts, flags := parseFlags(splitArgs(ev.Text), "desc", "sla", "due", "size")
+	if len(parts) > 2 && looksLikeURL(parts[2]) {
+		// Without a title the link would silently become one
+		sh.replyError(ev, t("add.title_first"))
+		return
+	}
 	if len(parts) < 4 {
-		sh.API.PostMessage(ev.Channel, slack.MsgOptionText("Usage: queue add <title> <MR link> @tag @tag", false))
+		sh.replyError(ev, t("add.usage"))
+		return
+	}
+	if !looksLikeURL(sh.MRLinks.expand(parts[3])) && slices.ContainsFunc(parts[4:], looksLikeURL) {
+		// An unquoted title spilled over into the link and tag positions
+		sh.replyError(ev, t("add.title_quotes"))
+		return
+	}
+	if err := sh.TextLimits.checkTitle(parts[2]); err != nil {
+		sh.replyError(ev, err.Error())
+		return
+	}
+	if err := sh.TextLimits.checkDescription(flags["desc"]); err != nil {
+		sh.replyError(ev, err.Error())
+		return
+	}
+
+	var size string
+	if value, ok := flags["size"]; ok {
+		var err error
+		if size, err = parseSize(value); err != nil {
+			sh.replyError(ev, err.Error())
+			return
+		}
+	}
+
+	var sla time.Duration
+	if value, ok := flags["sla"]; ok {
+		var err error
+		if sla, err = parseDuration(value); err != nil {
+			sh.replyError(ev, t("add.invalid_sla"))
+			return
+		}
+	}
+
+	var due time.Time
+	if value, ok := flags["due"]; ok {
+		if sla > 0 {
+			sh.replyError(ev, t("add.sla_or_due"))
+			return
+		}
+		var err error
+		if due, err = parseDue(value, time.Now().In(sh.Users.Location(ev.User))); err != nil {
+			sh.replyError(ev, err.Error())
+			return
+		}
+	}
+
+	mentions, labels := splitTagsAndLabels(parts[4:])
+	tags, unresolved := sh.normalizeTags(mentions)
+	if len(unresolved) > 0 {
+		sh.replyError(ev, t("add.unresolved", strings.Join(unresolved, ", "), ev.User))
 		return
 	}
 
+	var prefix string
+	if sh.IDs.Format == IDFormatPrefixed {
+		prefix = sh.IDs.prefix(ev.Channel)
+	}
+
+	dryRun := flags["dry-run"] == "true"
+
+	// Pick the reviewers, build the queue and create it under the lock, then
+	// reply after releasing it
 	sh.mu.Lock()
-	defer sh.mu.Unlock()
+	tags, skipped, err := sh.applyCapacity(tags)
+	if err == nil && len(tags) == 0 && !(dryRun && len(sh.Reviewers.Members()) > 0) {
+		// Fall back to the reviewer pool when no one was tagged; a dry run
+		// leaves the pool's rotation alone for the real add to pick from
+		tags, err = sh.poolReviewerTags(ev.User, skipped)
+	}
+	var queue *Queue
+	if err == nil {
+		// Only admins may broadcast through a queue's title or description
+		admin := sh.isAdmin(ev.User)
+		now := time.Now()
+		queue = &Queue{
+			Title:       sanitize(parts[2], admin),
+			MRLink:      sanitize(sh.MRLinks.expand(parts[3]), admin),
+			Description: sanitize(strings.TrimSpace(flags["desc"]), admin),
+			Tags:        tags,
+			Labels:      labels,
+			Size:        size,
+			Owner:       ev.User,
+			Status:      StatusOpen,
+			Channel:     ev.Channel,
+			TeamID:      sh.TeamID,
+			CreatedAt:   now,
+
+			SourceThreadTS: ev.ThreadTimeStamp,
+		}
+		queue.RequiredApprovals = sh.requiredApprovals(ev.Channel)
+		if sla > 0 {
+			queue.SLADeadline = now.Add(sla)
+		}
+		if !due.IsZero() {
+			queue.SLADeadline = due
+		}
+		if !dryRun {
+			var taken map[string]bool
+			if taken, err = sh.takenKeys(); err == nil {
+				queue.Key = sh.IDs.nextKey(prefix, taken)
+				err = sh.Store.Create(queue)
+			}
+			if err == nil {
+				sh.recordEvent(EventQueueAdded, queue, ev.User)
+			}
+		}
+	}
+	sh.mu.Unlock()
 
-	queue := &Queue{
-		ID:     sh.NextID,
-		Title:  parts[2],
-		MRLink: parts[3],
-		Tags:   parts[4:],
-		Owner:  ev.User,
+	if err != nil {
+		sh.replyQueueError(ev, err)
+		return
+	}
+	if dryRun {
+		sh.replyDryRun(ev, queue, skipped)
+		return
 	}
-	sh.Queues[sh.NextID] = queue
-	sh.NextID++
 
-	msg := fmt.Sprintf("Queue added: *%s*\nMR Link: %s\nTags: %s", queue.Title, queue.MRLink, strings.Join(queue.Tags, ", "))
-	sh.API.PostMessage(ev.Channel, slack.MsgOptionText(msg, false))
+	msg := t("add.done", queue.DisplayID(), queue.Title, queue.MRLink, strings.Join(queue.Tags, ", "))
+	if queue.Size != "" {
+		msg += t("add.size", queue.Size)
+	}
+	if sla > 0 {
+		msg += t("add.sla", formatDuration(sla))
+	}
+	if !due.IsZero() {
+		msg += t("queue.due_line", formatTimestamp(due, due.Location()))
+	}
+	if len(skipped) > 0 {
+		msg += t("add.skipped", strings.Join(skipped, ", "))
+	}
+	if queue.Description != "" {
+		msg += "\n" + formatDescription(queue.Description)
+	}
+	if channel, ts, err := sh.API.PostMessage(ev.Channel, slack.MsgOptionText(msg, false)); err == nil {
+		sh.trackReactions(queue, channel, ts)
+		sh.postStatusCard(queue, channel, ts)
+	}
+	// Reviewers tagged in a DM with the bot can't see it, so tell them directly
+	if isDirectMessage(ev) {
+		for _, tag := range queue.Tags {
+			sh.notifyAssigned(queue, tag, ev.User)
+		}
+	}
+}
+
+// poolReviewerTags picks a reviewer from the pool for a queue added without
+// tags. A queue without reviewers would count as approved straight away, so
+// it is rejected if the pool has no one available. Callers must hold sh.mu.
+func (sh *SlackHandler) poolReviewerTags(owner string, skipped []string) ([]string, error) {
+	reviewer, err := sh.nextPoolReviewer(owner)
+	if err != nil {
+		return nil, err
+	}
+	if reviewer == "" && len(skipped) > 0 {
+		return nil, rejection(t("add.all_over_capacity", strings.Join(skipped, ", ")))
+	}
+	if reviewer == "" {
+		return nil, rejection(t("add.need_reviewer"))
+	}
+	return []string{fmt.Sprintf("<@%s>", reviewer)}, nil
+}
+
+// replyDryRun shows what `queue add --dry-run` parsed, without creating the
+// queue.
+func (sh *SlackHandler) replyDryRun(ev *slackevents.MessageEvent, queue *Queue, skipped []string) {
+	tags := strings.Join(queue.Tags, ", ")
+	if tags == "" {
+		tags = t("dryrun.pool")
+	}
+	msg := t("dryrun.header", queue.Title, queue.MRLink, tags)
+	if len(queue.Labels) > 0 {
+		msg += t("add.labels", strings.Join(queue.Labels, ", "))
+	}
+	if queue.Size != "" {
+		msg += t("add.size", queue.Size)
+	}
+	if len(skipped) > 0 {
+		msg += t("add.skipped", strings.Join(skipped, ", "))
+	}
+	if !queue.SLADeadline.IsZero() {
+		msg += t("queue.due_line", formatTimestamp(queue.SLADeadline, sh.Users.Location(ev.User)))
+	}
+	if queue.Description != "" {
+		msg += "\n" + formatDescription(queue.Description)
+	}
+	sh.API.PostEphemeral(ev.Channel, ev.User, slack.MsgOptionText(msg, false))
 }
 
 func (sh *SlackHandler) handleQueueList(w http.ResponseWriter, ev *slackevents.MessageEvent) {
+	var label string
+	parts, flags := parseFlags(strings.Fields(ev.Text))
+	parts, sortKey := cutSortKey(parts)
+	parts, mode := cutListMode(parts)
+	archived := flags["archived"] == "true"
+	switch {
+	case len(parts) == 2:
+	case len(parts) == 4 && parts[2] == "label":
+		label = normalizeLabel(parts[3])
+	default:
+		sh.replyError(ev, t("list.usage"))
+		return
+	}
+
 	sh.mu.Lock()
-	defer sh.mu.Unlock()
+	queues, err := sh.teamQueues()
+	sh.mu.Unlock()
+
+	if label != "" {
+		queues = withLabel(queues, label)
+	}
+	queues = withArchived(queues, archived)
+	if !sortQueues(queues, sortKey, time.Now()) {
+		sh.replyError(ev, t("list.unknown_sort", sortKey))
+	}
+	if flags["json"] == "true" {
+		sh.replyQueueJSON(ev, queues, err)
+		return
+	}
+	sh.replyQueues(ev, queues, mode, err)
+}
+
+// replyQueueJSON posts the channel's queues as a JSON array in a code block.
+func (sh *SlackHandler) replyQueueJSON(ev *slackevents.MessageEvent, queues []*Queue, err error) {
+	if err != nil {
+		sh.replyQueueError(ev, err)
+		return
+	}
+	queues = sh.scopeQueues(queues, ev)
+	if queues == nil {
+		queues = []*Queue{}
+	}
 
-	if len(sh.Queues) == 0 {
-		sh.API.PostMessage(ev.Channel, slack.MsgOptionText("No queues available.", false))
+	// json.Marshal already escapes <, > and &, which Slack would otherwise
+	// treat as markup; backticks only occur inside strings, where the \u
+	// escape keeps them from closing the code block
+	data, err := json.MarshalIndent(queues, "", "  ")
+	if err != nil {
+		log.Printf("[ERROR] Failed to marshal queues: %v", err)
+		sh.replyError(ev, t("list.json_failed"))
 		return
 	}
+	text := "```\n" + strings.ReplaceAll(string(data), "`", `\u0060`) + "\n```"
+	sh.reply(ev, slack.MsgOptionText(text, false))
+}
+
+// inChannel keeps the queues that belong to the channel. Queues created
+// before channels were tracked are shown everywhere.
+func inChannel(queues []*Queue, channel string) []*Queue {
+	var scoped []*Queue
+	for _, queue := range queues {
+		if queue.Channel == "" || queue.Channel == channel {
+			scoped = append(scoped, queue)
+		}
+	}
+	return scoped
+}
 
+// approvalStatus lists who has approved the queue and who is still pending.
+func (q *Queue) approvalStatus() string {
+	approved, pending := t("approval.none"), t("approval.none")
+	if len(q.Approvers) > 0 {
+		approved = strings.Join(q.Approvers, " ")
+	}
+	if len(q.Tags) > 0 {
+		pending = strings.Join(q.Tags, " ")
+	}
+	if q.RequiredApprovals > 0 {
+		return t("approval.count", len(q.Approvers), q.RequiredApprovals, approved, pending)
+	}
+	return t("approval.tags", approved, pending)
+}
+
+// replyQueueList posts a snapshot taken under sh.mu, or reports the error
+// from taking it. It must be called without holding sh.mu.
+func (sh *SlackHandler) replyQueueList(ev *slackevents.MessageEvent, queues []*Queue, err error) {
+	sh.replyQueues(ev, withArchived(queues, false), "", err)
+}
+
+// replyQueues posts the given queues of the channel, archived or not, in the
+// list mode given or, if empty, the channel's default.
+func (sh *SlackHandler) replyQueues(ev *slackevents.MessageEvent, queues []*Queue, mode string, err error) {
+	if err != nil {
+		sh.replyQueueError(ev, err)
+		return
+	}
+	queues = sh.scopeQueues(queues, ev)
+
+	if mode == "" {
+		mode = sh.Channels.Settings(ev.Channel).ListMode
+	}
+
+	text := t("list.empty")
+	switch {
+	case len(queues) == 0:
+	case mode == ListModeCompact:
+		text = sh.Users.Unmention(renderCompactList(queues))
+	default:
+		text = sh.Users.Unmention(renderQueueList(queues, time.Now(), sh.Users.Location(ev.User)))
+	}
+	sh.reply(ev, slack.MsgOptionText(text, false))
+}
+
+// List modes selectable via LIST_MODE, the list_mode channel setting or
+// `queue list compact|full`.
+const (
+	// ListModeFull shows each queue's link, reviewers, labels and timing.
+	ListModeFull = "full"
+	// ListModeCompact shows one line per queue with only its ID and title.
+	ListModeCompact = "compact"
+)
+
+func validListMode(mode string) bool {
+	return mode == ListModeFull || mode == ListModeCompact
+}
+
+// cutListMode removes a list mode given right after the subcommand, e.g.
+// "queue list compact", returning it or "" if there is none.
+func cutListMode(parts []string) ([]string, string) {
+	if len(parts) > 2 && validListMode(parts[2]) {
+		return append(parts[:2:2], parts[3:]...), parts[2]
+	}
+	return parts, ""
+}
+
+// renderCompactList formats queues one line each, with only the ID and
+// title.
+func renderCompactList(queues []*Queue) string {
+	var queueList strings.Builder
+	for _, queue := range queues {
+		queueList.WriteString(t("list.compact_queue", queue.DisplayID(), queue.Title))
+	}
+	return queueList.String()
+}
+
+// renderQueueList formats a snapshot of queues for the list reply, with
+// timestamps in loc. It does not touch handler state, so it needs no lock.
+func renderQueueList(queues []*Queue, now time.Time, loc *time.Location) string {
 	var queueList strings.Builder
-	for _, queue := range sh.Queues {
-		mention := ""
-		if queue.InReviewState {
-			mention = fmt.Sprintf("Owner: <@%s>", queue.Owner)
+	for _, queue := range queues {
+		timing := ""
+		if !queue.CreatedAt.IsZero() {
+			timing = t("list.created", formatTimestamp(queue.CreatedAt, loc))
+		}
+		if queue.isOverdue(now) {
+			timing += t("list.overdue", formatDuration(now.Sub(queue.SLADeadline)))
+		} else if !queue.SLADeadline.IsZero() {
+			timing += t("list.due", formatTimestamp(queue.SLADeadline, loc))
+		}
+		if queue.isSnoozed(now) {
+			timing += t("list.snoozed", formatTimestamp(queue.SnoozedUntil, loc))
+		}
+
+		status := queue.status()
+		mention := t("list.status", status.label())
+		if status == StatusInReview {
+			mention += t("list.owner", queue.Owner)
+			if queue.Reviewer != "" {
+				mention += t("list.reviewer", queue.Reviewer)
+			}
 		} else {
-			mention = fmt.Sprintf("Tags: %s", strings.Join(queue.Tags, ", "))
+			mention += queue.approvalStatus()
+		}
+
+		labels := ""
+		if len(queue.Labels) > 0 {
+			labels = t("list.labels", strings.Join(queue.Labels, ", "))
+		}
+		if queue.Size != "" {
+			labels += t("list.size", queue.Size)
+		}
+		if done, total := queue.checklistProgress(); total > 0 {
+			labels += t("list.checklist", done, total)
 		}
 
-		queueList.WriteString(fmt.Sprintf("ID: %d | Title: %s | MR: %s | %s\n",
-			queue.ID, queue.Title, queue.MRLink, mention))
+		queueList.WriteString(t("list.queue",
+			queue.statusEmoji(now), queue.DisplayID(), queue.Title, queue.MRLink, mention, labels, timing))
+		if queue.Description != "" {
+			queueList.WriteString(formatDescription(queue.Description))
+		}
 	}
-	sh.API.PostMessage(ev.Channel, slack.MsgOptionText(queueList.String(), false))
+	return queueList.String()
 }
 
 func (sh *SlackHandler) handleQueueRemove(w http.ResponseWriter, ev *slackevents.MessageEvent) {
-	id, err := parseQueueID(ev.Text)
+	args, flags := parseFlags(strings.Fields(ev.Text))
+	refs, err := sh.parseQueueIDs(strings.Join(args, " "))
 	if err != nil {
-		sh.API.PostMessage(ev.Channel, slack.MsgOptionText(err.Error(), false))
+		sh.replyError(ev, err.Error())
+		return
+	}
+	if sh.ConfirmRemove && flags["yes"] != "true" {
+		sh.replyError(ev, t("remove.confirm", strings.Join(refs, " ")))
 		return
 	}
 
 	sh.mu.Lock()
-	defer sh.mu.Unlock()
+	var removed []Queue
+	var summary strings.Builder
+	var lastErr error
+	for _, ref := range refs {
+		queue, err := sh.removeQueue(ref, ev.User)
+		if err != nil {
+			lastErr = err
+			summary.WriteString(t("bulk.result", ref, queueErrorSummary(err)))
+			continue
+		}
+		removed = append(removed, *queue)
+		summary.WriteString(t("remove.removed_one", ref))
+	}
+	sh.pushUndo(ev.Channel, ev.User, removed)
+	sh.mu.Unlock()
 
-	if _, exists := sh.Queues[id]; !exists {
-		sh.API.PostMessage(ev.Channel, slack.MsgOptionText("Queue not found.", false))
+	if len(refs) == 1 {
+		if lastErr != nil {
+			sh.replyQueueError(ev, lastErr)
+			return
+		}
+		sh.API.PostMessage(ev.Channel, slack.MsgOptionText(sh.withPrefix(t("remove.done")), false))
 		return
 	}
-
-	delete(sh.Queues, id)
-	sh.API.PostMessage(ev.Channel, slack.MsgOptionText("Queue removed.", false))
+	sh.API.PostMessage(ev.Channel, slack.MsgOptionText(summary.String(), false))
 }
 
-func (sh *SlackHandler) handleQueueApprove(w http.ResponseWriter, ev *slackevents.MessageEvent) {
-	parts := strings.Fields(ev.Text)
-	if len(parts) < 3 {
-		sh.API.PostMessage(ev.Channel, slack.MsgOptionText("Usage: queue approve <id>", false))
+func (sh *SlackHandler) handleQueueClear(w http.ResponseWriter, ev *slackevents.MessageEvent) {
+	args, flags := parseFlags(strings.Fields(ev.Text))
+	if len(args) != 2 {
+		sh.replyError(ev, t("clear.usage"))
+		return
+	}
+	if !sh.isAdmin(ev.User) {
+		sh.replyError(ev, t("clear.admin_only"))
+		return
+	}
+	all := flags["all"] == "true"
+	confirmed := flags["yes"] == "true"
+
+	sh.mu.Lock()
+	queues, err := sh.teamQueues()
+	if err == nil && !all {
+		queues = inChannel(queues, ev.Channel)
+	}
+	var removed []Queue
+	failed := 0
+	if err == nil && confirmed {
+		for _, queue := range queues {
+			if err := sh.deleteQueue(queue, ev.User); err != nil {
+				failed++
+				log.Printf("[ERROR] Failed to clear queue %s: %v", queue.DisplayID(), err)
+				continue
+			}
+			removed = append(removed, *queue)
+		}
+		sh.pushUndo(ev.Channel, ev.User, removed)
+	}
+	sh.mu.Unlock()
+
+	if err != nil {
+		sh.replyQueueError(ev, err)
+		return
+	}
+	if len(queues) == 0 {
+		sh.replyError(ev, t("clear.empty"))
+		return
+	}
+	if !confirmed {
+		confirm := t("clear.confirm_channel", len(queues))
+		if all {
+			confirm = t("clear.confirm_all", len(queues))
+		}
+		sh.replyError(ev, confirm)
 		return
 	}
 
-	id, err := strconv.Atoi(parts[2])
+	msg := t("clear.done", len(removed))
+	if failed > 0 {
+		msg += t("clear.failed", failed)
+	}
+	sh.API.PostMessage(ev.Channel, slack.MsgOptionText(sh.withPrefix(msg), false))
+}
+
+// removeQueue deletes a queue on behalf of actor, returning the removed
+// queue. Callers must hold sh.mu.
+func (sh *SlackHandler) removeQueue(ref, actor string) (*Queue, error) {
+	queue, err := sh.findQueue(ref)
+	if err != nil {
+		return nil, err
+	}
+	if err := sh.deleteQueue(queue, actor); err != nil {
+		return nil, err
+	}
+	return queue, nil
+}
+
+// deleteQueue deletes a queue on behalf of actor, unpinning its review
+// message and recording the removal. Callers must hold sh.mu.
+func (sh *SlackHandler) deleteQueue(queue *Queue, actor string) error {
+	if err := sh.Store.Delete(queue.ID); err != nil {
+		return err
+	}
+
+	sh.unpinReviewMessage(takeReviewPin(queue))
+	sh.retireStatusCard(queue)
+	sh.recordEvent(EventQueueRemoved, queue, actor)
+	return nil
+}
+
+// queueErrorSummary describes a failed queue operation in a bulk summary.
+func queueErrorSummary(err error) string {
+	var reason rejection
+	switch {
+	case errors.As(err, &reason):
+		return string(reason)
+	case errors.Is(err, ErrQueueNotFound):
+		return t("bulk.not_found")
+	default:
+		log.Printf("[ERROR] Queue store failure: %v", err)
+		return t("bulk.failed")
+	}
+}
+
+func (sh *SlackHandler) handleQueueApprove(w http.ResponseWriter, ev *slackevents.MessageEvent) {
+	refs, err := sh.parseQueueIDs(ev.Text)
 	if err != nil {
-		sh.API.PostMessage(ev.Channel, slack.MsgOptionText("Invalid queue ID.", false))
+		sh.replyError(ev, err.Error())
 		return
 	}
 
+	// Apply the approvals and snapshot the result under the lock, then reply
+	// after releasing it
 	sh.mu.Lock()
-	queue, exists := sh.Queues[id]
-	if !exists {
-		sh.mu.Unlock() // Release lock before returning
-		sh.API.PostMessage(ev.Channel, slack.MsgOptionText("Queue not found.", false))
+	var reply string
+	var completed []*Queue
+	if len(refs) == 1 {
+		var queue *Queue
+		if reply, queue, err = sh.approveQueueRef(refs[0], ev.User); err == nil && queue.status() == StatusApproved {
+			completed = append(completed, queue)
+		}
+	} else {
+		var summary strings.Builder
+		for _, ref := range refs {
+			result, queue, err := sh.approveQueueRef(ref, ev.User)
+			if err != nil {
+				result = queueErrorSummary(err)
+			} else if queue.status() == StatusApproved {
+				completed = append(completed, queue)
+			}
+			summary.WriteString(t("bulk.result", ref, result))
+		}
+		reply = summary.String()
+	}
+	queues, listErr := sh.teamQueues()
+	sh.mu.Unlock()
+
+	if err != nil {
+		sh.replyQueueError(ev, err)
 		return
 	}
+	sh.API.PostMessage(ev.Channel, slack.MsgOptionText(reply, false))
+	for _, queue := range completed {
+		if queue.Owner != ev.User {
+			sh.notifyOwnerApproved(queue)
+		}
+	}
+	sh.replyQueueList(ev, queues, listErr)
+}
 
-	if len(queue.Tags) > 0 {
-		approvedTag := fmt.Sprintf("<@%s>", ev.User) // Format user ID as a Slack tag
-		tagIndex := -1
+// notifyOwnerApproved lets the owner know their queue is ready to merge,
+// by DM or, if that fails, with a mention in the queue's channel.
+func (sh *SlackHandler) notifyOwnerApproved(queue *Queue) {
+	msg := t("approve.owner_dm", queue.DisplayID(), queue.Title, queue.MRLink)
+	owner, channel, id, title := queue.Owner, queue.Channel, queue.DisplayID(), queue.Title
+	sh.DMs.Send(sh.API, sh.TeamID, owner, msg, func() {
+		if channel == "" {
+			return
+		}
+		msg := t("approve.owner_mention", owner, id, title)
+		if _, _, err := sh.API.PostMessage(channel, slack.MsgOptionText(msg, false)); err != nil {
+			log.Printf("[ERROR] Failed to notify owner %s of queue %s: %v", owner, id, err)
+		}
+	})
+}
+
+// approveQueueRef applies the user's approval to the referenced queue as a
+// single store update, returning the outcome and the updated queue. Callers
+// must hold sh.mu.
+func (sh *SlackHandler) approveQueueRef(ref, userID string) (string, *Queue, error) {
+	queue, err := sh.findQueue(ref)
+	if err != nil {
+		return "", nil, err
+	}
+	return sh.approveQueueID(queue.ID, userID)
+}
 
-		// Find the tag to remove
-		for i, tag := range queue.Tags {
-			if tag == approvedTag {
-				tagIndex = i
-				break
+// approveQueueID is approveQueueRef for a queue already looked up. Callers
+// must hold sh.mu.
+func (sh *SlackHandler) approveQueueID(id int, userID string) (string, *Queue, error) {
+	var msg string
+	var completed bool
+	var pin reviewPin
+	queue, err := sh.Store.Update(id, func(q *Queue) error {
+		completed, pin = false, reviewPin{}
+		if err := q.checkTransition(StatusApproved); err != nil {
+			return err
+		}
+		var ok bool
+		if msg, ok = sh.approveQueue(q, userID); !ok {
+			return rejection(msg)
+		}
+		q.UpdatedAt = time.Now()
+		q.resetReminders()
+		if q.isComplete() {
+			completed = true
+			q.transition(StatusApproved)
+			q.ApprovedAt = q.UpdatedAt
+			pin = takeReviewPin(q)
+			if sh.AutoArchive {
+				q.ArchivedAt = q.UpdatedAt
 			}
 		}
+		return nil
+	})
+	if err != nil {
+		return msg, queue, err
+	}
 
-		if tagIndex != -1 {
-			// Remove the tag
-			queue.Tags = append(queue.Tags[:tagIndex], queue.Tags[tagIndex+1:]...)
-			sh.mu.Unlock() // Release lock after update
-			sh.API.PostMessage(ev.Channel, slack.MsgOptionText("Queue approved and tag removed.", false))
-		} else {
-			sh.mu.Unlock() // Release lock
-			sh.API.PostMessage(ev.Channel, slack.MsgOptionText("Your tag was not found in the queue.", false))
-			return
+	sh.recordEvent(EventQueueApproved, queue, userID)
+	if completed {
+		sh.unpinReviewMessage(pin)
+		sh.recordEvent(EventQueueCompleted, queue, userID)
+		if queue.isArchived() {
+			sh.recordEvent(EventQueueArchived, queue, userID)
 		}
-	} else {
+	}
+	sh.refreshStatusCard(queue)
+	return msg, queue, nil
+}
+
+// Approve modes selectable via APPROVE_MODE.
+const (
+	// ApproveModeTag removes the approver's tag; the queue completes when no
+	// tags are left.
+	ApproveModeTag = "tag"
+	// ApproveModeCount leaves tags alone and completes the queue once it has
+	// RequiredApprovals distinct approvals.
+	ApproveModeCount = "count"
+)
+
+// requiredApprovals returns the RequiredApprovals for a new queue in the
+// channel.
+func (sh *SlackHandler) requiredApprovals(channel string) int {
+	if settings := sh.Channels.Settings(channel); settings.ApproveMode == ApproveModeCount {
+		return settings.RequiredApprovals
+	}
+	return 0
+}
+
+// approveQueue applies the user's approval to the queue according to the
+// queue's approve mode. It returns the outcome message and whether the
+// approval was accepted. It only changes the queue, so it can run inside
+// Store.Update.
+func (sh *SlackHandler) approveQueue(queue *Queue, userID string) (string, bool) {
+	if userID == queue.Owner && !sh.AllowSelfApprove {
+		return t("approve.self"), false
+	}
+	if sh.RequireChecklist && !queue.checklistDone() && queue.completedBy(userID) {
+		done, total := queue.checklistProgress()
+		return t("approve.checklist", done, total), false
+	}
+	if queue.RequiredApprovals > 0 {
+		return sh.countApproval(queue, userID)
+	}
+	return sh.removeApprovedTag(queue, userID)
+}
+
+// removeApprovedTag implements ApproveModeTag: the approver's tag moves from
+// pending to approved, and the queue completes once no tags are left.
+func (sh *SlackHandler) removeApprovedTag(queue *Queue, userID string) (string, bool) {
+	if len(queue.Tags) == 0 {
 		// No tags left, mark as complete
-		sh.mu.Unlock() // Release lock
-		sh.API.PostMessage(ev.Channel, slack.MsgOptionText("Queue completed; no tags left.", false))
+		return t("approve.no_tags"), true
+	}
+
+	approvedTag := fmt.Sprintf("<@%s>", userID) // Format user ID as a Slack tag
+	tagIndex := -1
+
+	// Find the tag to remove
+	for i, tag := range queue.Tags {
+		if tag == approvedTag {
+			tagIndex = i
+			break
+		}
+	}
+	if tagIndex == -1 {
+		return t("approve.tag_not_found"), false
+	}
+
+	// Move the tag from pending to approved
+	queue.Tags = append(queue.Tags[:tagIndex], queue.Tags[tagIndex+1:]...)
+	queue.Approvers = append(queue.Approvers, approvedTag)
+	return t("approve.tag_removed"), true
+}
+
+// countApproval implements ApproveModeCount: anyone may approve once, tags
+// are left untouched, and the queue completes at RequiredApprovals.
+func (sh *SlackHandler) countApproval(queue *Queue, userID string) (string, bool) {
+	approver := fmt.Sprintf("<@%s>", userID)
+	for _, existing := range queue.Approvers {
+		if existing == approver {
+			return t("approve.already"), false
+		}
+	}
+	if len(queue.Approvers) >= queue.RequiredApprovals {
+		return t("approve.enough", queue.RequiredApprovals), false
 	}
 
-	// Show the updated list of queues
-	sh.handleQueueList(w, ev) // This will use the current queue state
+	queue.Approvers = append(queue.Approvers, approver)
+	if len(queue.Approvers) < queue.RequiredApprovals {
+		return t("approve.counted", len(queue.Approvers), queue.RequiredApprovals), true
+	}
+	return t("approve.completed", len(queue.Approvers)), true
 }
 
 func (sh *SlackHandler) handleQueueReview(w http.ResponseWriter, ev *slackevents.MessageEvent) {
-	id, err := parseQueueID(ev.Text)
+	ref, err := sh.parseQueueID(ev.Text)
 	if err != nil {
-		sh.API.PostMessage(ev.Channel, slack.MsgOptionText(err.Error(), false))
+		sh.replyError(ev, err.Error())
 		return
 	}
 
 	sh.mu.Lock() // Locking the mutex
-	queue, exists := sh.Queues[id]
-	if !exists {
+	queue, err := sh.findQueue(ref)
+	if err == nil {
+		queue, err = sh.Store.Update(queue.ID, func(q *Queue) error {
+			if q.status() == StatusInReview && q.Reviewer != "" && q.Reviewer != ev.User {
+				return rejection(t("review.taken", q.Reviewer))
+			}
+			if err := q.transition(StatusInReview); err != nil {
+				return err
+			}
+			q.Reviewer = ev.User
+			q.UpdatedAt = time.Now()
+			q.resetReminders()
+			return nil
+		})
+	}
+	if err != nil {
 		sh.mu.Unlock() // Unlocking before early return
-		sh.API.PostMessage(ev.Channel, slack.MsgOptionText("Queue not found.", false))
+		sh.replyQueueError(ev, err)
 		return
 	}
-
-	queue.InReviewState = true
-	msg := fmt.Sprintf("Queue %d is now in review.", queue.ID)
-	sh.API.PostMessage(ev.Channel, slack.MsgOptionText(msg, false))
-
-	// Unlock the mutex before calling handleQueueList
+	sh.recordEvent(EventQueueReviewed, queue, ev.User)
+	sh.refreshStatusCard(queue)
+	queues, listErr := sh.teamQueues()
 	sh.mu.Unlock()
 
-	// Now call handleQueueList without holding the mutex
-	sh.handleQueueList(w, ev)
+	msg := t("review.done", queue.DisplayID())
+	if channel, ts, err := sh.API.PostMessage(ev.Channel, slack.MsgOptionText(msg, false)); err == nil {
+		sh.pinReviewMessage(queue.ID, channel, ts)
+	}
+	sh.replyQueueList(ev, queues, listErr)
 }
 
 func (sh *SlackHandler) handleQueueUpdate(w http.ResponseWriter, ev *slackevents.MessageEvent) {
-	id, err := parseQueueID(ev.Text)
+	ref, err := sh.parseQueueID(ev.Text)
 	if err != nil {
-		sh.API.PostMessage(ev.Channel, slack.MsgOptionText(err.Error(), false))
+		sh.replyError(ev, err.Error())
 		return
 	}
 
 	sh.mu.Lock() // Locking the mutex
-	queue, exists := sh.Queues[id]
-	if !exists {
+	var released string
+	var pin reviewPin
+	queue, err := sh.findQueue(ref)
+	if err == nil {
+		queue, err = sh.Store.Update(queue.ID, func(q *Queue) error {
+			if q.Reviewer != "" && q.Reviewer != ev.User && !sh.isAdmin(ev.User) {
+				return rejection(t("update.reviewer_only", q.Reviewer))
+			}
+			if err := q.checkActive(); err != nil {
+				return err
+			}
+			if err := q.transition(StatusOpen); err != nil {
+				return err
+			}
+			released = q.Reviewer
+			q.Reviewer = ""
+			pin = takeReviewPin(q)
+			q.UpdatedAt = time.Now()
+			q.resetReminders()
+			return nil
+		})
+	}
+	if err != nil {
 		sh.mu.Unlock() // Unlocking before early return
-		sh.API.PostMessage(ev.Channel, slack.MsgOptionText("Queue not found.", false))
+		sh.replyQueueError(ev, err)
 		return
 	}
+	sh.unpinReviewMessage(pin)
+	sh.refreshHomes(queue)
+	sh.refreshUserHomes(queue.TeamID, released)
+	sh.refreshStatusCard(queue)
+	queues, listErr := sh.teamQueues()
+	sh.mu.Unlock()
 
-	queue.InReviewState = false
-	msg := fmt.Sprintf("Queue %d has been updated and is no longer in review.", queue.ID)
+	msg := t("update.done", queue.DisplayID())
 	sh.API.PostMessage(ev.Channel, slack.MsgOptionText(msg, false))
+	sh.replyQueueList(ev, queues, listErr)
+}
+
+func (sh *SlackHandler) handleQueueDesc(w http.ResponseWriter, ev *slackevents.MessageEvent) {
+	parts := splitArgs(ev.Text)
+	if len(parts) < 4 {
+		sh.replyError(ev, t("desc.usage"))
+		return
+	}
+
+	if !sh.validQueueID(parts[2]) {
+		sh.replyError(ev, t("queue.invalid_id"))
+		return
+	}
+	if err := sh.TextLimits.checkDescription(strings.Join(parts[3:], " ")); err != nil {
+		sh.replyError(ev, err.Error())
+		return
+	}
 
-	// Unlock the mutex before calling handleQueueList
+	description := sanitize(strings.TrimSpace(strings.Join(parts[3:], " ")), sh.isAdmin(ev.User))
+	sh.mu.Lock()
+	queue, err := sh.findQueue(parts[2])
+	if err == nil {
+		queue, err = sh.Store.Update(queue.ID, func(q *Queue) error {
+			q.Description = description
+			q.UpdatedAt = time.Now()
+			return nil
+		})
+	}
+	if err == nil {
+		sh.refreshHomes(queue)
+	}
 	sh.mu.Unlock()
 
-	// Now call handleQueueList without holding the mutex
-	sh.handleQueueList(w, ev)
+	if err != nil {
+		sh.replyQueueError(ev, err)
+		return
+	}
+	if queue.Description == "" {
+		sh.API.PostMessage(ev.Channel, slack.MsgOptionText(t("desc.cleared", queue.DisplayID()), false))
+		return
+	}
+	msg := t("desc.updated", queue.DisplayID(), formatDescription(queue.Description))
+	sh.API.PostMessage(ev.Channel, slack.MsgOptionText(msg, false))
 }
 
-func (sh *SlackHandler) handleQueueHelp(w http.ResponseWriter, ev *slackevents.MessageEvent) {
-	helpMessage := `Here are the available queue commands:
-- ` + "`queue add <title> <link> @tag @tag...`" + `: Adds a queue with a title, link, and optional tags (user mentions)
-  Example: ` + "`queue add \"New Feature\" https://example.com @user1 @user2`" + `
-- ` + "`queue list`" + `: Lists all queues
-- ` + "`queue remove <queueID>`" + `: Removes a queue by ID
-- ` + "`queue approve <queueID>`" + `: Approves a queue by ID
-- ` + "`queue review <queueID>`" + `: Marks a queue as under review
-- ` + "`queue update <queueID>`" + `: Updates a queue
-- ` + "`queue help`" + `: Displays this help message`
+func (sh *SlackHandler) handleReviewers(w http.ResponseWriter, ev *slackevents.MessageEvent) {
+	parts := strings.Fields(ev.Text)
+	if len(parts) < 2 {
+		sh.replyError(ev, t("reviewers.usage"))
+		return
+	}
+
+	switch parts[1] {
+	case "list":
+		members := sh.Reviewers.Members()
+		if len(members) == 0 {
+			sh.reply(ev, slack.MsgOptionText(t("reviewers.empty"), false))
+			return
+		}
+		mentions := make([]string, len(members))
+		for i, member := range members {
+			mentions[i] = fmt.Sprintf("<@%s>", member)
+		}
+		sh.reply(ev, slack.MsgOptionText(t("reviewers.list", strings.Join(mentions, ", ")), false))
+	case "add", "remove":
+		if !sh.isAdmin(ev.User) {
+			sh.replyError(ev, t("reviewers.admin_only"))
+			return
+		}
+		if len(parts) < 3 {
+			sh.replyError(ev, t("reviewers.usage_one", parts[1]))
+			return
+		}
+		userID, ok := parseMention(parts[2])
+		if !ok {
+			sh.replyError(ev, t("reviewers.not_mention", parts[2]))
+			return
+		}
+		if parts[1] == "add" {
+			user, err := sh.API.GetUserInfo(userID)
+			if err != nil || user.Deleted || user.IsBot {
+				sh.replyError(ev, t("reviewers.inactive", parts[2]))
+				return
+			}
+		}
+
+		var changed bool
+		var err error
+		if parts[1] == "add" {
+			changed, err = sh.Reviewers.Add(userID)
+		} else {
+			changed, err = sh.Reviewers.Remove(userID)
+		}
+		if err != nil {
+			log.Printf("[ERROR] Failed to save reviewer pool: %v", err)
+			sh.replyError(ev, t("reviewers.save_failed"))
+			return
+		}
 
-	// Send the help message to the Slack channel
-	sh.API.PostMessage(ev.Channel, slack.MsgOptionText(helpMessage, false))
+		msg := t("reviewers.added", userID)
+		switch {
+		case parts[1] == "add" && !changed:
+			msg = t("reviewers.already", userID)
+		case parts[1] == "remove" && changed:
+			msg = t("reviewers.removed", userID)
+		case parts[1] == "remove":
+			msg = t("reviewers.absent", userID)
+		}
+		sh.API.PostMessage(ev.Channel, slack.MsgOptionText(msg, false))
+	default:
+		sh.replyError(ev, t("reviewers.usage"))
+	}
 }
 
-func parseQueueID(command string) (int, error) {
-	parts := strings.Fields(command)
-	if len(parts) < 3 {
-		return 0, fmt.Errorf("Usage: <command> <id>")
+// reply posts a response the user asked for, such as the list, to the
+// command's channel. If Slack rejects it, the user is told privately rather
+// than left with no answer.
+func (sh *SlackHandler) reply(ev *slackevents.MessageEvent, options ...slack.MsgOption) {
+	if _, _, err := sh.API.PostMessage(ev.Channel, options...); err != nil {
+		sh.replyError(ev, t("slack.post_failed"))
 	}
+}
 
-	id, err := strconv.Atoi(parts[2])
-	if err != nil {
-		return 0, fmt.Errorf("Invalid queue ID.")
+// replyError sends a usage or validation error only to the invoking user, so
+// mistyped commands don't add noise to the channel.
+func (sh *SlackHandler) replyError(ev *slackevents.MessageEvent, msg string) {
+	text := slack.MsgOptionText(sh.withPrefix(msg), false)
+	if sh.responseURL != "" {
+		_, _, err := sh.API.PostMessage(ev.Channel, text, slack.MsgOptionResponseURL(sh.responseURL, slack.ResponseTypeEphemeral))
+		if err == nil {
+			return
+		}
+		log.Printf("[WARN] Failed to reply to %s through the response URL: %v", ev.User, err)
+	}
+	if _, err := sh.API.PostEphemeral(ev.Channel, ev.User, text); err != nil {
+		log.Printf("[ERROR] Failed to post ephemeral reply to %s: %v", ev.User, err)
 	}
-	return id, nil
 }
@@ -0,0 +1,796 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/slack-go/slack/slackevents"
+)
+
+const testSigningSecret = "8f742231b10e8888abcd99yyyzzz85a5"
+
+// newTestHandler returns a handler backed by a fakeAPI and a MemoryStore.
+// configure, if not nil, adjusts the config before the handler is built.
+func newTestHandler(t *testing.T, configure func(cfg *Config)) (*SlackHandler, *fakeAPI) {
+	t.Helper()
+	cfg := &Config{
+		SigningSecret:     testSigningSecret,
+		EventsPath:        "/events-endpoint",
+		IDFormat:          IDFormatNumeric,
+		IDPrefixes:        make(map[string]string),
+		MaxBodyBytes:      1 << 20,
+		CommandPrefix:     defaultCommandPrefix,
+		ApproveMode:       ApproveModeTag,
+		ListMode:          ListModeFull,
+		Locale:            LocaleEnglish,
+		RequiredApprovals: 1,
+		NotifyBatchWindow: time.Millisecond,
+	}
+	if configure != nil {
+		configure(cfg)
+	}
+	channels, err := NewChannelConfigs("", cfg.ChannelDefaults())
+	if err != nil {
+		t.Fatalf("NewChannelConfigs: %v", err)
+	}
+	reviewers, err := NewReviewerPool("")
+	if err != nil {
+		t.Fatalf("NewReviewerPool: %v", err)
+	}
+	api := &fakeAPI{}
+	sh, err := NewSlackHandler(cfg, api, NewMemoryStore(), reviewers, channels, nil, NewWebhookNotifier(""))
+	if err != nil {
+		t.Fatalf("NewSlackHandler: %v", err)
+	}
+	return sh, api
+}
+
+// command runs text as a message from user in channel C1 and returns once
+// the handler has finished.
+func command(sh *SlackHandler, user, text string) {
+	sh.dispatchCommand(httptest.NewRecorder(), &slackevents.MessageEvent{
+		Type:      "message",
+		User:      user,
+		Channel:   "C1",
+		Text:      text,
+		TimeStamp: "1000.000001",
+	})
+}
+
+// mustGet returns the stored queue or fails the test.
+func mustGet(t *testing.T, sh *SlackHandler, id int) *Queue {
+	t.Helper()
+	queue, err := sh.Store.Get(id)
+	if err != nil {
+		t.Fatalf("Get(%d): %v", id, err)
+	}
+	return queue
+}
+
+func TestHandlerCommands(t *testing.T) {
+	tests := []struct {
+		name string
+		// setup runs before the command, as user U1
+		setup []string
+		user  string
+		text  string
+		// want is a substring of the last message sent
+		want string
+		// ephemeral reports whether the last message was only shown to user
+		ephemeral bool
+	}{
+		{
+			name: "add",
+			user: "U1",
+			text: `queue add "New feature" https://example.com/mr/1 <@U2>`,
+			want: "Queue 1 added: *New feature*",
+		},
+		{
+			name:      "add without arguments",
+			user:      "U1",
+			text:      "queue add",
+			want:      "Usage: queue add",
+			ephemeral: true,
+		},
+		{
+			name:  "review",
+			setup: []string{`queue add "New feature" https://example.com/mr/1 <@U2>`},
+			user:  "U2",
+			text:  "queue review 1",
+			want:  "New feature",
+		},
+		{
+			name:  "approve",
+			setup: []string{`queue add "New feature" https://example.com/mr/1 <@U2> <@U3>`},
+			user:  "U2",
+			text:  "queue approve 1",
+			want:  "New feature",
+		},
+		{
+			name:      "approve without a tag",
+			setup:     []string{`queue add "New feature" https://example.com/mr/1 <@U2>`},
+			user:      "U3",
+			text:      "queue approve 1",
+			want:      "Your tag was not found",
+			ephemeral: true,
+		},
+		{
+			name:      "approve unknown queue",
+			user:      "U2",
+			text:      "queue approve 7",
+			want:      "Queue not found.",
+			ephemeral: true,
+		},
+		{
+			name:  "remove",
+			setup: []string{`queue add "New feature" https://example.com/mr/1 <@U2>`},
+			user:  "U1",
+			text:  "queue remove 1",
+			want:  "Queue removed.",
+		},
+		{
+			name:      "unknown command",
+			user:      "U1",
+			text:      "queue frobnicate",
+			want:      "Unknown command.",
+			ephemeral: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sh, api := newTestHandler(t, nil)
+			for _, text := range tt.setup {
+				command(sh, "U1", text)
+			}
+			api.reset()
+
+			command(sh, tt.user, tt.text)
+			got := api.last()
+			if !strings.Contains(got.Text, tt.want) {
+				t.Errorf("last message = %q, want it to contain %q", got.Text, tt.want)
+			}
+			if ephemeral := got.User != ""; ephemeral != tt.ephemeral {
+				t.Errorf("ephemeral = %v, want %v", ephemeral, tt.ephemeral)
+			}
+		})
+	}
+}
+
+func TestHandlerQueueLifecycle(t *testing.T) {
+	sh, _ := newTestHandler(t, nil)
+
+	command(sh, "U1", `queue add "New feature" https://example.com/mr/1 <@U2> <@U3>`)
+	if queue := mustGet(t, sh, 1); queue.status() != StatusOpen || len(queue.Tags) != 2 {
+		t.Fatalf("after add: status %s, tags %v", queue.status(), queue.Tags)
+	}
+
+	command(sh, "U2", "queue review 1")
+	if queue := mustGet(t, sh, 1); queue.status() != StatusInReview || queue.Reviewer != "U2" {
+		t.Fatalf("after review: status %s, reviewer %q", queue.status(), queue.Reviewer)
+	}
+
+	command(sh, "U2", "queue approve 1")
+	if queue := mustGet(t, sh, 1); queue.status() == StatusApproved || len(queue.Tags) != 1 {
+		t.Fatalf("after first approval: status %s, tags %v", queue.status(), queue.Tags)
+	}
+
+	command(sh, "U3", "queue approve 1")
+	queue := mustGet(t, sh, 1)
+	if queue.status() != StatusApproved {
+		t.Fatalf("after second approval: status %s", queue.status())
+	}
+	if want := []string{"<@U2>", "<@U3>"}; strings.Join(queue.Approvers, " ") != strings.Join(want, " ") {
+		t.Errorf("approvers = %v, want %v", queue.Approvers, want)
+	}
+
+	command(sh, "U1", "queue remove 1")
+	if _, err := sh.Store.Get(1); err != ErrQueueNotFound {
+		t.Errorf("after remove: Get err = %v, want ErrQueueNotFound", err)
+	}
+}
+
+func TestURLVerification(t *testing.T) {
+	sh, _ := newTestHandler(t, nil)
+	body := `{"token":"Jhj5dZrVaK7ZwHHjRyZWjbDl","challenge":"3eZbrw1aBm2rZgRNFdxV2595E9CY3gmdALWMmHkvFXO7tYXAYM8P","type":"url_verification"}`
+
+	rec := httptest.NewRecorder()
+	sh.HandleEventEndpoint(rec, signedRequest("/events-endpoint", body))
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "text/plain" {
+		t.Errorf("Content-Type = %q, want text/plain", got)
+	}
+	if got, want := rec.Body.String(), "3eZbrw1aBm2rZgRNFdxV2595E9CY3gmdALWMmHkvFXO7tYXAYM8P"; got != want {
+		t.Errorf("body = %q, want the challenge %q", got, want)
+	}
+}
+
+func TestEventEndpointAcknowledgesMessage(t *testing.T) {
+	sh, api := newTestHandler(t, nil)
+
+	rec := httptest.NewRecorder()
+	sh.HandleEventEndpoint(rec, signedRequest("/events-endpoint", messageEvent("U1", "queue list")))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	// The 200 goes out before the command runs; wait for it so the test
+	// doesn't end with the handler still running
+	if !eventually(t, func() bool { return len(api.sent()) > 0 }) {
+		t.Error("the message event was acknowledged but never handled")
+	}
+}
+
+func TestNewSlackHandlerAuthFailure(t *testing.T) {
+	cfg := &Config{IDFormat: IDFormatNumeric, CommandPrefix: defaultCommandPrefix}
+	channels, _ := NewChannelConfigs("", cfg.ChannelDefaults())
+	reviewers, _ := NewReviewerPool("")
+	api := &fakeAPI{authErr: errors.New("invalid_auth")}
+
+	sh, err := NewSlackHandler(cfg, api, NewMemoryStore(), reviewers, channels, nil, NewWebhookNotifier(""))
+	if err == nil {
+		t.Fatal("NewSlackHandler succeeded with a failing AuthTest")
+	}
+	if sh != nil {
+		t.Errorf("handler = %v, want nil on error", sh)
+	}
+	if !strings.Contains(err.Error(), "invalid_auth") {
+		t.Errorf("error = %q, want it to wrap the AuthTest error", err)
+	}
+}
+
+// TestConcurrentApproveAndList approves, reviews and lists the same queues
+// from many goroutines at once. Run it with -race.
+func TestConcurrentApproveAndList(t *testing.T) {
+	sh, _ := newTestHandler(t, nil)
+	const queues = 5
+	for i := 0; i < queues; i++ {
+		command(sh, "U1", `queue add "Feature" https://example.com/mr/1 <@U2> <@U3>`)
+	}
+
+	var wg sync.WaitGroup
+	for i := 1; i <= queues; i++ {
+		id := strconv.Itoa(i)
+		for _, run := range []struct{ user, text string }{
+			{"U2", "queue review " + id},
+			{"U2", "queue approve " + id},
+			{"U3", "queue approve " + id},
+			{"U1", "queue list"},
+			{"U3", "queue info " + id},
+		} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				command(sh, run.user, run.text)
+			}()
+		}
+	}
+	wg.Wait()
+	sh.DMs.Flush()
+
+	for i := 1; i <= queues; i++ {
+		if queue := mustGet(t, sh, i); queue.status() != StatusApproved {
+			t.Errorf("queue %d: status %s, want approved", i, queue.status())
+		}
+	}
+}
+
+func TestAddWithoutTags(t *testing.T) {
+	t.Run("empty pool", func(t *testing.T) {
+		sh, api := newTestHandler(t, nil)
+
+		command(sh, "U1", `queue add "New feature" https://example.com/mr/1`)
+		if got := api.last(); got.User != "U1" || !strings.Contains(got.Text, "Tag at least one reviewer") {
+			t.Errorf("reply = %+v, want the need-reviewer error", got)
+		}
+		if queues, _ := sh.Store.List(); len(queues) != 0 {
+			t.Errorf("a tagless queue was created: %+v", queues[0])
+		}
+	})
+
+	t.Run("pool", func(t *testing.T) {
+		sh, _ := newTestHandler(t, nil)
+		sh.Reviewers.Add("U1")
+		sh.Reviewers.Add("U2")
+
+		command(sh, "U1", `queue add "New feature" https://example.com/mr/1`)
+		queue := mustGet(t, sh, 1)
+		if strings.Join(queue.Tags, " ") != "<@U2>" {
+			t.Errorf("tags = %v, want the pool reviewer other than the owner", queue.Tags)
+		}
+		if queue.status() != StatusOpen {
+			t.Errorf("status = %s, want open", queue.status())
+		}
+	})
+}
+
+func TestApproveModes(t *testing.T) {
+	t.Run("tag", func(t *testing.T) {
+		sh, api := newTestHandler(t, nil)
+		command(sh, "U1", `queue add "New feature" https://example.com/mr/1 <@U2> <@U3>`)
+
+		command(sh, "U4", "queue approve 1")
+		if got := api.last(); !strings.Contains(got.Text, "Your tag was not found") {
+			t.Errorf("untagged approval: reply %q", got.Text)
+		}
+		command(sh, "U2", "queue approve 1")
+		queue := mustGet(t, sh, 1)
+		if strings.Join(queue.Tags, " ") != "<@U3>" || strings.Join(queue.Approvers, " ") != "<@U2>" {
+			t.Errorf("after U2: tags %v, approvers %v", queue.Tags, queue.Approvers)
+		}
+		if queue.status() == StatusApproved {
+			t.Error("queue completed with a tag still pending")
+		}
+		command(sh, "U3", "queue approve 1")
+		if queue := mustGet(t, sh, 1); queue.status() != StatusApproved || len(queue.Tags) != 0 {
+			t.Errorf("after U3: status %s, tags %v", queue.status(), queue.Tags)
+		}
+	})
+
+	t.Run("count", func(t *testing.T) {
+		sh, api := newTestHandler(t, func(cfg *Config) {
+			cfg.ApproveMode = ApproveModeCount
+			cfg.RequiredApprovals = 2
+		})
+		command(sh, "U1", `queue add "New feature" https://example.com/mr/1 <@U2>`)
+
+		command(sh, "U3", "queue approve 1")
+		queue := mustGet(t, sh, 1)
+		if queue.status() == StatusApproved || strings.Join(queue.Tags, " ") != "<@U2>" {
+			t.Errorf("after one approval: status %s, tags %v", queue.status(), queue.Tags)
+		}
+		command(sh, "U3", "queue approve 1")
+		if got := api.last(); !strings.Contains(got.Text, "already approved") {
+			t.Errorf("repeated approval: reply %q", got.Text)
+		}
+		command(sh, "U2", "queue approve 1")
+		queue = mustGet(t, sh, 1)
+		if queue.status() != StatusApproved {
+			t.Errorf("after two approvals: status %s", queue.status())
+		}
+		if strings.Join(queue.Approvers, " ") != "<@U3> <@U2>" || strings.Join(queue.Tags, " ") != "<@U2>" {
+			t.Errorf("approvers %v, tags %v; count mode must leave tags alone", queue.Approvers, queue.Tags)
+		}
+	})
+}
+
+func TestAddRequiresTitle(t *testing.T) {
+	for _, text := range []string{
+		"queue add https://example.com/mr/1 <@U2>",
+		"queue add https://example.com/mr/1",
+	} {
+		sh, api := newTestHandler(t, nil)
+		command(sh, "U1", text)
+		if got := api.last(); got.User != "U1" || !strings.Contains(got.Text, "needs a title before the link") {
+			t.Errorf("%q: reply %+v, want the missing-title hint", text, got)
+		}
+		if queues, _ := sh.Store.List(); len(queues) != 0 {
+			t.Errorf("%q: created a queue titled %q", text, queues[0].Title)
+		}
+	}
+}
+
+func TestSelfApprovePolicy(t *testing.T) {
+	for _, allow := range []bool{false, true} {
+		sh, api := newTestHandler(t, func(cfg *Config) { cfg.AllowSelfApprove = allow })
+		command(sh, "U1", `queue add "New feature" https://example.com/mr/1 <@U1>`)
+
+		command(sh, "U1", "queue approve 1")
+		queue := mustGet(t, sh, 1)
+		if allow {
+			if queue.status() != StatusApproved {
+				t.Errorf("ALLOW_SELF_APPROVE=true: status %s, want approved", queue.status())
+			}
+			continue
+		}
+		if got := api.last(); got.User != "U1" || !strings.Contains(got.Text, "can't approve your own MR") {
+			t.Errorf("ALLOW_SELF_APPROVE=false: reply %+v", got)
+		}
+		if queue.status() == StatusApproved || len(queue.Approvers) > 0 {
+			t.Errorf("ALLOW_SELF_APPROVE=false: status %s, approvers %v", queue.status(), queue.Approvers)
+		}
+	}
+}
+
+func TestAddTextLimits(t *testing.T) {
+	sh, api := newTestHandler(t, func(cfg *Config) { cfg.TextLimits = TextLimits{MinTitle: 3, MinDescription: 5} })
+
+	command(sh, "U1", `queue add "ab" https://example.com/mr/1 <@U2>`)
+	if got := api.last(); got.User != "U1" || !strings.Contains(got.Text, "title is too short") {
+		t.Errorf("short title: reply %+v", got)
+	}
+	command(sh, "U1", `queue add "abc" https://example.com/mr/1 <@U2> --desc "abcd"`)
+	if got := api.last(); got.User != "U1" || !strings.Contains(got.Text, "description is too short") {
+		t.Errorf("short description: reply %+v", got)
+	}
+	command(sh, "U1", `queue add "abc" https://example.com/mr/1 <@U2> --desc "abcde"`)
+	if queue := mustGet(t, sh, 1); queue.Title != "abc" || queue.Description != "abcde" {
+		t.Errorf("queue at the minimums: %+v", queue)
+	}
+}
+
+// heldBy reports whether mu is locked. Only meaningful while no other
+// goroutine may be holding it.
+func heldBy(mu *sync.Mutex) func() bool {
+	return func() bool {
+		if mu.TryLock() {
+			mu.Unlock()
+			return false
+		}
+		return true
+	}
+}
+
+// TestRepliesSentAfterUnlock runs commands one at a time and checks that
+// none of them posts to Slack while holding sh.mu.
+func TestRepliesSentAfterUnlock(t *testing.T) {
+	sh, api := newTestHandler(t, func(cfg *Config) {
+		cfg.Admins = []string{"U9"}
+		// Keep batched DMs from being sent from a timer while a handler
+		// holds the lock
+		cfg.NotifyBatchWindow = time.Hour
+	})
+	api.locked = heldBy(sh.mu)
+
+	for _, run := range []struct{ user, text string }{
+		{"U1", `queue add "New feature" https://example.com/mr/1 <@U2> <@U3>`},
+		{"U1", `queue add "Dry run" https://example.com/mr/2 <@U2> --dry-run`},
+		{"U1", `queue add "Untagged" https://example.com/mr/2`},
+		{"U1", `queue add "Second" https://example.com/mr/3 <@U3> fixme`},
+		{"U1", "queue list"},
+		{"U1", "queue info 1"},
+		{"U1", `queue desc 1 "More context"`},
+		{"U2", "queue review 1"},
+		{"U2", "queue update 1"},
+		{"U2", "queue approve 1"},
+		{"U2", "queue approve 1 2"},
+		{"U1", "queue checklist 1 add \"tests\""},
+		{"U1", "queue snooze 1 2h"},
+		{"U1", "queue assign 2 <@U2>"},
+		{"U1", "queue tags 2 add <@U3>"},
+		{"U1", "queue reassign 2 <@U2>"},
+		{"U2", "queue take 2"},
+		{"U3", "queue next"},
+		{"U1", "queue stats"},
+		{"U1", "queue remove 2"},
+		{"U1", "queue undo"},
+		{"U1", "queue undo"},
+		{"U1", "queue close 2"},
+		{"U9", "queue clear"},
+		{"U1", "queue remove 1 2"},
+		{"U1", "queue undo"},
+		{"U9", "queue clear --yes"},
+		{"U1", "queue whoami"},
+	} {
+		command(sh, run.user, run.text)
+	}
+	if api.lockedPosts != nil {
+		t.Errorf("messages posted while holding sh.mu: %q", api.lockedPosts)
+	}
+}
+
+// TestConcurrentHandlers runs a mix of commands, reactions and background
+// jobs at once, so -race can see handlers that touch shared state without
+// sh.mu.
+func TestConcurrentHandlers(t *testing.T) {
+	sh, _ := newTestHandler(t, func(cfg *Config) {
+		cfg.Admins = []string{"U9"}
+		cfg.StatusCards = true
+		cfg.PinReviews = true
+		cfg.Reactions = ReactionConfig{Approve: []string{"white_check_mark"}, RequestChanges: []string{"x"}}
+	})
+	sh.Reviewers.Add("U3")
+	for i := 0; i < 3; i++ {
+		command(sh, "U1", `queue add "Feature" https://example.com/mr/1 <@U2> --sla=1m`)
+	}
+	// The commands below leave queue 4 to the reaction
+	command(sh, "U1", `queue add "Reacted" https://example.com/mr/3 <@U2>`)
+	reacted := mustGet(t, sh, 4)
+	if reacted.ThreadTS == "" {
+		t.Fatal("queue 4 has no message to react to")
+	}
+	sh.handleAppHomeOpened(&slackevents.AppHomeOpenedEvent{User: "U2", Tab: "home"})
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 3; i++ {
+		id := strconv.Itoa(i)
+		for _, run := range []struct{ user, text string }{
+			{"U1", `queue add "More" https://example.com/mr/2`},
+			{"U2", "queue review " + id},
+			{"U2", "queue approve " + id},
+			{"U1", "queue list"},
+			{"U1", "queue info " + id},
+			{"U1", `queue desc ` + id + ` "Context"`},
+			{"U1", "queue checklist " + id + ` add "tests"`},
+			{"U1", "queue snooze " + id + " off"},
+			{"U1", "queue assign " + id + " <@U3>"},
+			{"U3", "queue next"},
+			{"U1", "queue remove " + id},
+			{"U1", "queue undo"},
+			{"U1", "queue stats"},
+			{"U9", "queue archive " + id},
+			{"U1", "queue whoami"},
+		} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				command(sh, run.user, run.text)
+			}()
+		}
+	}
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		sh.remindOverdue(time.Now().Add(time.Hour), ReminderConfig{Interval: time.Millisecond, EscalateAfter: 1})
+	}()
+	go func() {
+		defer wg.Done()
+		sh.archiveApproved(time.Now().Add(time.Hour), time.Minute)
+	}()
+	go func() {
+		defer wg.Done()
+		sh.handleReactionAdded(&slackevents.ReactionAddedEvent{
+			User:     "U2",
+			Reaction: "white_check_mark",
+			Item:     slackevents.Item{Type: "message", Channel: reacted.StatusChannel, Timestamp: reacted.ThreadTS},
+		})
+	}()
+	wg.Wait()
+	sh.DMs.Flush()
+
+	var approved, completed bool
+	for _, entry := range sh.Audit.Since(time.Time{}) {
+		if entry.QueueID == reacted.ID && entry.Actor == "U2" {
+			approved = approved || entry.Event == EventQueueApproved
+			completed = completed || entry.Event == EventQueueCompleted
+		}
+	}
+	if !approved || !completed {
+		t.Errorf("reaction approval not audited: approved %v, completed %v", approved, completed)
+	}
+}
+
+// retryingStore runs every Update callback twice, once on a copy that is
+// thrown away, the way RedisStore does after a conflicting write.
+type retryingStore struct {
+	*MemoryStore
+}
+
+func (rs retryingStore) Update(id int, fn func(q *Queue) error) (*Queue, error) {
+	if queue, err := rs.MemoryStore.Get(id); err == nil {
+		fn(queue)
+	}
+	return rs.MemoryStore.Update(id, fn)
+}
+
+func TestUpdateCallbacksMayRunTwice(t *testing.T) {
+	sh, _ := newTestHandler(t, nil)
+	sh.Store = retryingStore{NewMemoryStore()}
+	start := time.Now()
+
+	command(sh, "U1", `queue add "New feature" https://example.com/mr/1 <@U2> <@U3>`)
+	command(sh, "U2", "queue assign 1 <@U4>")
+	command(sh, "U2", "queue approve 1")
+	command(sh, "U3", "queue approve 1")
+	command(sh, "U4", "queue approve 1")
+
+	queue := mustGet(t, sh, 1)
+	if queue.status() != StatusApproved || strings.Join(queue.Approvers, " ") != "<@U2> <@U3> <@U4>" {
+		t.Errorf("status %s, approvers %v", queue.status(), queue.Approvers)
+	}
+	counts := make(map[string]int)
+	for _, entry := range sh.Audit.Since(start) {
+		counts[entry.Event]++
+	}
+	if counts[EventQueueApproved] != 3 || counts[EventQueueCompleted] != 1 {
+		t.Errorf("events %v, want 3 approvals and 1 completion", counts)
+	}
+}
+
+func TestWebhookPayloadAfterApproval(t *testing.T) {
+	events := make(chan WebhookEvent, 16)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event WebhookEvent
+		json.NewDecoder(r.Body).Decode(&event)
+		events <- event
+	}))
+	defer srv.Close()
+
+	sh, _ := newTestHandler(t, nil)
+	sh.Webhook = NewWebhookNotifier(srv.URL)
+	command(sh, "U1", `queue add "New feature" https://example.com/mr/1 <@U2>`)
+	command(sh, "U2", "queue approve 1")
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case event := <-events:
+			if event.Event != EventQueueCompleted {
+				continue
+			}
+			if event.Queue.Status != StatusApproved || event.Queue.ApprovedAt.IsZero() || len(event.Queue.Tags) != 0 {
+				t.Errorf("completed payload: status %s, approved at %v, tags %v", event.Queue.Status, event.Queue.ApprovedAt, event.Queue.Tags)
+			}
+			return
+		case <-timeout:
+			t.Fatal("no queue.completed webhook was delivered")
+		}
+	}
+}
+
+// otherTeam returns a handler for workspace T2 that shares sh's queues, as
+// forTeam would for a second installation.
+func otherTeam(sh *SlackHandler) *SlackHandler {
+	th := *sh
+	th.TeamID = "T2"
+	return &th
+}
+
+func TestTeamScoping(t *testing.T) {
+	sh, api := newTestHandler(t, func(cfg *Config) {
+		cfg.Admins = []string{"U9", "T2:U8"}
+	})
+	other := otherTeam(sh)
+
+	command(sh, "U1", `queue add "New feature" https://example.com/mr/1 <@U2>`)
+	legacy := &Queue{Title: "Legacy", Owner: "U1", Channel: "C1", CreatedAt: time.Now()}
+	if err := sh.Store.Create(legacy); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	for _, tt := range []struct {
+		user string
+		text string
+		want string
+	}{
+		{"U1", "queue list", "No queues available."},
+		{"U1", "queue info 1", "Queue not found."},
+		{"U2", "queue approve 1", "Queue not found."},
+		{"U1", "queue remove 1", "Queue not found."},
+		{"U1", "queue info " + strconv.Itoa(legacy.ID), "Queue not found."},
+		{"U1", "queue info https://example.com/mr/1", "Queue not found."},
+		{"U9", "queue clear --all --yes", "Only admins"},
+		{"U8", "queue clear --all --yes", "There are no queues to clear."},
+	} {
+		api.reset()
+		command(other, tt.user, tt.text)
+		if got := api.last().Text; !strings.Contains(got, tt.want) {
+			t.Errorf("%s in T2: last message = %q, want it to contain %q", tt.text, got, tt.want)
+		}
+	}
+	if queues, _ := sh.Store.List(); len(queues) != 2 {
+		t.Fatalf("T2 changed T1's queues: %d left, want 2", len(queues))
+	}
+
+	api.reset()
+	command(sh, "U1", "queue list")
+	if got := api.last().Text; !strings.Contains(got, "New feature") || !strings.Contains(got, "Legacy") {
+		t.Errorf("list in T1 = %q, want both queues", got)
+	}
+	if !sh.isAdmin("U9") || sh.isAdmin("U8") || other.isAdmin("U9") || !other.isAdmin("U8") {
+		t.Error("ADMIN_USERS entries applied to the wrong workspace")
+	}
+}
+
+func TestTagsSetReconcilesApprovers(t *testing.T) {
+	t.Run("tag", func(t *testing.T) {
+		tests := []struct {
+			set           string
+			wantTags      string
+			wantApprovers string
+		}{
+			{"<@U3>", "<@U3>", ""},
+			{"<@U2> <@U3>", "<@U3>", "<@U2>"},
+			{"<@U3> <@U4>", "<@U3> <@U4>", ""},
+		}
+		for _, tt := range tests {
+			sh, _ := newTestHandler(t, nil)
+			command(sh, "U1", `queue add "New feature" https://example.com/mr/1 <@U2> <@U3>`)
+			command(sh, "U2", "queue approve 1")
+
+			command(sh, "U1", "queue tags 1 set "+tt.set)
+			queue := mustGet(t, sh, 1)
+			if tags, approvers := strings.Join(queue.Tags, " "), strings.Join(queue.Approvers, " "); tags != tt.wantTags || approvers != tt.wantApprovers {
+				t.Errorf("set %s: tags %q, approvers %q; want %q, %q", tt.set, tags, approvers, tt.wantTags, tt.wantApprovers)
+			}
+		}
+
+		sh, api := newTestHandler(t, nil)
+		command(sh, "U1", `queue add "New feature" https://example.com/mr/1 <@U2> <@U3>`)
+		command(sh, "U2", "queue approve 1")
+		command(sh, "U1", "queue tags 1 set <@U2>")
+		if got := api.last(); !strings.Contains(got.Text, "already approved") {
+			t.Errorf("setting only approved tags: reply %q, want a rejection", got.Text)
+		}
+	})
+
+	t.Run("count", func(t *testing.T) {
+		sh, _ := newTestHandler(t, func(cfg *Config) {
+			cfg.ApproveMode = ApproveModeCount
+			cfg.RequiredApprovals = 3
+		})
+		command(sh, "U1", `queue add "New feature" https://example.com/mr/1 <@U2> <@U3>`)
+		command(sh, "U2", "queue approve 1")
+		command(sh, "U4", "queue approve 1")
+
+		command(sh, "U1", "queue tags 1 set <@U3>")
+		queue := mustGet(t, sh, 1)
+		if approvers := strings.Join(queue.Approvers, " "); approvers != "<@U4>" {
+			t.Errorf("approvers = %q, want only the untagged approver left", approvers)
+		}
+	})
+}
+
+func TestUnassignKeepsOneTag(t *testing.T) {
+	sh, api := newTestHandler(t, nil)
+	command(sh, "U1", `queue add "New feature" https://example.com/mr/1 <@U2> <@U3>`)
+
+	command(sh, "U1", "queue unassign 1 <@U2>")
+	if tags := strings.Join(mustGet(t, sh, 1).Tags, " "); tags != "<@U3>" {
+		t.Fatalf("tags = %q, want <@U3>", tags)
+	}
+
+	command(sh, "U1", "queue unassign 1 <@U3>")
+	if got := api.last(); !strings.Contains(got.Text, "at least one pending reviewer") {
+		t.Errorf("unassigning the last tag: reply %q, want a rejection", got.Text)
+	}
+	if tags := strings.Join(mustGet(t, sh, 1).Tags, " "); tags != "<@U3>" {
+		t.Errorf("tags = %q, want the last tag kept", tags)
+	}
+
+	// A queue without tags would let anyone complete it
+	command(sh, "U4", "queue approve 1")
+	if queue := mustGet(t, sh, 1); queue.status() == StatusApproved {
+		t.Error("an untagged user approved the queue")
+	}
+}
+
+func TestPingSkipsApprovers(t *testing.T) {
+	sh, api := newTestHandler(t, func(cfg *Config) {
+		cfg.ApproveMode = ApproveModeCount
+		cfg.RequiredApprovals = 3
+	})
+	command(sh, "U1", `queue add "New feature" https://example.com/mr/1 <@U2> <@U3>`)
+	command(sh, "U2", "queue approve 1")
+
+	command(sh, "U1", "queue ping 1")
+	if got := api.last().Text; !strings.Contains(got, "<@U3>") || strings.Contains(got, "<@U2>") {
+		t.Errorf("ping = %q, want only the reviewer who hasn't approved", got)
+	}
+
+	now := time.Now()
+	if _, err := sh.Store.Update(1, func(q *Queue) error {
+		q.SLADeadline = now.Add(-time.Hour)
+		return nil
+	}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	api.reset()
+	sh.remindOverdue(now, ReminderConfig{Interval: time.Minute, EscalateAfter: 1, EscalationTarget: "<@U9>"})
+	sent := api.sent()
+	if len(sent) == 0 {
+		t.Fatal("no reminder was sent")
+	}
+	for _, msg := range sent {
+		if !strings.Contains(msg.Text, "<@U3>") || strings.Contains(msg.Text, "<@U2>") {
+			t.Errorf("reminder = %q, want only the reviewer who hasn't approved", msg.Text)
+		}
+	}
+
+	command(sh, "U3", "queue approve 1")
+	sh.Store.Update(1, func(q *Queue) error {
+		q.LastPingedAt = time.Time{}
+		return nil
+	})
+	command(sh, "U1", "queue ping 1")
+	if got := api.last(); got.User != "U1" || !strings.Contains(got.Text, "No reviewers are pending") {
+		t.Errorf("ping with every tag approved = %+v, want a rejection", got)
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"net/url"
+)
+
+// slashQueueAdd opens the add modal. The command must be configured in the
+// app settings with /commands as its request URL.
+const slashQueueAdd = "/queue-add"
+
+// HandleSlashCommand handles the bot's slash commands.
+func (sh *SlackHandler) HandleSlashCommand(w http.ResponseWriter, r *http.Request) {
+	body, ok := sh.readVerifiedBody(w, r)
+	if !ok {
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		log.Printf("[ERROR] Failed to parse slash command form: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	command := form.Get("command")
+	if command != slashQueueAdd {
+		log.Printf("[WARN] Unsupported slash command: %s", command)
+		// A plain text response is shown to the invoking user only
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte(t("slash.unknown", command)))
+		return
+	}
+
+	th := sh.forTeam(form.Get("team_id")).withResponseURL(form.Get("response_url"))
+	// The trigger ID is valid for 3 seconds, so open the modal right after the ack
+	sh.ackAndRun(w, func(w http.ResponseWriter) {
+		th.openAddModal(form.Get("trigger_id"), form.Get("user_id"), form.Get("channel_id"), "", "")
+	})
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+// SnapshotConfig controls the periodic JSON backup of all queues.
+type SnapshotConfig struct {
+	// Path of the snapshot file. Empty disables snapshots.
+	Path string
+	// Interval between snapshots.
+	Interval time.Duration
+}
+
+// loadSnapshot restores queues from the snapshot file into an empty store. A
+// missing file is not an error.
+func (sh *SlackHandler) loadSnapshot(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read snapshot: %w", err)
+	}
+
+	var queues []*Queue
+	if err := json.Unmarshal(data, &queues); err != nil {
+		return fmt.Errorf("parse snapshot: %w", err)
+	}
+
+	// Never overwrite state that a shared store already holds
+	existing, err := sh.Store.List()
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		log.Printf("[WARN] Queue store is not empty; ignoring snapshot %s", path)
+		return nil
+	}
+
+	for _, queue := range queues {
+		if err := sh.Store.Save(queue); err != nil {
+			return fmt.Errorf("restore queue %d: %w", queue.ID, err)
+		}
+	}
+	log.Printf("[INFO] Restored %d queues from snapshot %s", len(queues), path)
+	return nil
+}
+
+// SaveSnapshot writes all queues to the snapshot file. It is a no-op when
+// snapshots are disabled.
+func (sh *SlackHandler) SaveSnapshot(cfg SnapshotConfig) error {
+	if cfg.Path == "" {
+		return nil
+	}
+
+	queues, err := sh.Store.List()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(queues, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(cfg.Path, data)
+}
+
+// StartSnapshots launches a background loop that saves a snapshot every
+// interval.
+func (sh *SlackHandler) StartSnapshots(cfg SnapshotConfig) {
+	if cfg.Path == "" || cfg.Interval <= 0 {
+		return
+	}
+
+	log.Printf("[INFO] Saving queue snapshots to %s every %s", cfg.Path, cfg.Interval)
+	go func() {
+		ticker := time.NewTicker(cfg.Interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := sh.SaveSnapshot(cfg); err != nil {
+				log.Printf("[ERROR] Failed to save queue snapshot: %v", err)
+			}
+		}
+	}()
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// isSnoozed reports whether reminders for the queue are paused at now.
+func (q *Queue) isSnoozed(now time.Time) bool {
+	return now.Before(q.SnoozedUntil)
+}
+
+// parseSnoozeArgs validates "queue snooze <id> <duration|off>" and returns the
+// queue reference and how long to snooze for, zero meaning off.
+func (sh *SlackHandler) parseSnoozeArgs(command string) (string, time.Duration, error) {
+	parts := strings.Fields(command)
+	if len(parts) != 4 {
+		return "", 0, errors.New(t("snooze.usage"))
+	}
+	if !sh.validQueueID(parts[2]) {
+		return "", 0, errors.New(t("queue.invalid_id"))
+	}
+	if strings.EqualFold(parts[3], "off") {
+		return parts[2], 0, nil
+	}
+	d, err := parseDuration(parts[3])
+	if err != nil {
+		return "", 0, errors.New(t("snooze.invalid_duration"))
+	}
+	return parts[2], d, nil
+}
+
+// handleQueueSnooze pauses the overdue reminders for a queue that is waiting
+// on something else, or resumes them with "off".
+func (sh *SlackHandler) handleQueueSnooze(w http.ResponseWriter, ev *slackevents.MessageEvent) {
+	ref, d, err := sh.parseSnoozeArgs(ev.Text)
+	if err != nil {
+		sh.replyError(ev, err.Error())
+		return
+	}
+
+	now := time.Now()
+	sh.mu.Lock()
+	queue, err := sh.findQueue(ref)
+	if err == nil {
+		queue, err = sh.Store.Update(queue.ID, func(q *Queue) error {
+			if q.Owner != ev.User && !sh.isAdmin(ev.User) {
+				return rejection(t("snooze.owner_only", q.Owner))
+			}
+			if d == 0 {
+				if !q.isSnoozed(now) {
+					return rejection(t("snooze.not_snoozed", q.DisplayID()))
+				}
+				q.SnoozedUntil = time.Time{}
+			} else {
+				if err := q.checkActive(); err != nil {
+					return err
+				}
+				q.SnoozedUntil = now.Add(d)
+			}
+			q.UpdatedAt = now
+			return nil
+		})
+	}
+	sh.mu.Unlock()
+
+	if err != nil {
+		sh.replyQueueError(ev, err)
+		return
+	}
+	msg := t("snooze.resumed", queue.DisplayID())
+	if d != 0 {
+		msg = t("snooze.done", queue.DisplayID(), formatTimestamp(queue.SnoozedUntil, sh.Users.Location(ev.User)))
+	}
+	sh.API.PostMessage(ev.Channel, slack.MsgOptionText(msg, false))
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// List orders selectable with `queue list sort=<key>`.
+const (
+	SortByID       = "id"
+	SortByAge      = "age"
+	SortByPriority = "priority"
+)
+
+// cutSortKey removes a sort=<key> argument from parts, returning the rest and
+// the key, which is SortByID when there is none.
+func cutSortKey(parts []string) ([]string, string) {
+	key := SortByID
+	var rest []string
+	for _, part := range parts {
+		if value, ok := strings.CutPrefix(part, "sort="); ok {
+			key = strings.ToLower(value)
+			continue
+		}
+		rest = append(rest, part)
+	}
+	return rest, key
+}
+
+// moreUrgent orders queues by priority: overdue first, then due soonest, then
+// oldest.
+func moreUrgent(a, b *Queue, now time.Time) bool {
+	if a.isOverdue(now) != b.isOverdue(now) {
+		return a.isOverdue(now)
+	}
+	if a.SLADeadline.IsZero() != b.SLADeadline.IsZero() {
+		return !a.SLADeadline.IsZero()
+	}
+	if !a.SLADeadline.Equal(b.SLADeadline) {
+		return a.SLADeadline.Before(b.SLADeadline)
+	}
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.ID < b.ID
+}
+
+// sortQueues orders queues in place by key. It reports false for an unknown
+// key, leaving them ordered by ID.
+func sortQueues(queues []*Queue, key string, now time.Time) bool {
+	switch key {
+	case SortByAge:
+		sort.SliceStable(queues, func(i, j int) bool {
+			if !queues[i].CreatedAt.Equal(queues[j].CreatedAt) {
+				return queues[i].CreatedAt.Before(queues[j].CreatedAt)
+			}
+			return queues[i].ID < queues[j].ID
+		})
+	case SortByPriority:
+		sort.SliceStable(queues, func(i, j int) bool { return moreUrgent(queues[i], queues[j], now) })
+	default:
+		sort.SliceStable(queues, func(i, j int) bool { return queues[i].ID < queues[j].ID })
+		return key == SortByID
+	}
+	return true
+}
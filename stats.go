@@ -0,0 +1,250 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+const (
+	defaultStatsWindow = 7 * 24 * time.Hour
+	leaderboardSize    = 5
+)
+
+// queueStats aggregates audit entries over a time window. Approved counts
+// distinct queues that got all the approvals they need, while Approvals
+// counts every approval per reviewer.
+type queueStats struct {
+	Created   int
+	Approved  int
+	Removed   int
+	Approvals map[string]int
+}
+
+func aggregateStats(entries []AuditEntry) queueStats {
+	stats := queueStats{Approvals: make(map[string]int)}
+	approved := make(map[int]bool)
+	for _, entry := range entries {
+		switch entry.Event {
+		case EventQueueAdded:
+			stats.Created++
+		case EventQueueApproved:
+			stats.Approvals[entry.Actor]++
+		case EventQueueCompleted:
+			if !approved[entry.QueueID] {
+				approved[entry.QueueID] = true
+				stats.Approved++
+			}
+		case EventQueueRemoved:
+			stats.Removed++
+		}
+	}
+	return stats
+}
+
+// averageReviewTime averages the time in review of the queues approved since
+// the given time. Queues approved without ever being claimed are left out.
+func averageReviewTime(queues []*Queue, since time.Time) (time.Duration, int) {
+	var total time.Duration
+	var n int
+	for _, queue := range queues {
+		if queue.ApprovedAt.Before(since) || queue.ReviewTime == 0 {
+			continue
+		}
+		total += queue.ReviewTime
+		n++
+	}
+	if n == 0 {
+		return 0, 0
+	}
+	return total / time.Duration(n), n
+}
+
+// leaderboard returns reviewers ordered by approval count, highest first.
+func (qs queueStats) leaderboard() []string {
+	reviewers := make([]string, 0, len(qs.Approvals))
+	for reviewer := range qs.Approvals {
+		reviewers = append(reviewers, reviewer)
+	}
+	sort.Slice(reviewers, func(i, j int) bool {
+		if qs.Approvals[reviewers[i]] != qs.Approvals[reviewers[j]] {
+			return qs.Approvals[reviewers[i]] > qs.Approvals[reviewers[j]]
+		}
+		return reviewers[i] < reviewers[j]
+	})
+	return reviewers
+}
+
+// personalStats summarizes one reviewer's activity: approvals over a window,
+// and the active queues currently waiting on them.
+type personalStats struct {
+	Approved   int
+	Turnaround time.Duration // average time from creation to their approval
+	Timed      int           // approvals whose queue creation is still in the audit log
+	Assigned   int
+	Overdue    int
+}
+
+// aggregatePersonalStats counts the approvals userID made in entries, timing
+// each from the queue's creation in history, and the queues still waiting on
+// them.
+func aggregatePersonalStats(userID string, entries, history []AuditEntry, queues []*Queue, now time.Time) personalStats {
+	created := make(map[int]time.Time)
+	for _, entry := range history {
+		if entry.Event == EventQueueAdded {
+			created[entry.QueueID] = entry.Time
+		}
+	}
+
+	var stats personalStats
+	var total time.Duration
+	approved := make(map[int]bool)
+	for _, entry := range entries {
+		if entry.Event != EventQueueApproved || entry.Actor != userID || approved[entry.QueueID] {
+			continue
+		}
+		approved[entry.QueueID] = true
+		stats.Approved++
+		if createdAt, ok := created[entry.QueueID]; ok {
+			total += entry.Time.Sub(createdAt)
+			stats.Timed++
+		}
+	}
+	if stats.Timed > 0 {
+		stats.Turnaround = total / time.Duration(stats.Timed)
+	}
+
+	tag := fmt.Sprintf("<@%s>", userID)
+	for _, queue := range queues {
+		if !queue.isActive() {
+			continue
+		}
+		if queue.Reviewer != userID && (!queue.hasTag(tag) || slices.Contains(queue.Approvers, tag)) {
+			continue
+		}
+		stats.Assigned++
+		if queue.isOverdue(now) {
+			stats.Overdue++
+		}
+	}
+	return stats
+}
+
+// handleMyStats shows the asking reviewer their own numbers, privately.
+func (sh *SlackHandler) handleMyStats(ev *slackevents.MessageEvent, window time.Duration) {
+	now := time.Now()
+	sh.mu.Lock()
+	queues, err := sh.teamQueues()
+	sh.mu.Unlock()
+	if err != nil {
+		sh.replyQueueError(ev, err)
+		return
+	}
+
+	entries := sh.teamEntries(sh.Audit.Since(now.Add(-window)))
+	stats := aggregatePersonalStats(ev.User, entries, sh.teamEntries(sh.Audit.Since(time.Time{})), queues, now)
+	if stats.Approved == 0 && stats.Assigned == 0 {
+		sh.replyError(ev, t("stats.me_none", formatDuration(window)))
+		return
+	}
+
+	title := t("stats.me_title", formatDuration(window))
+	header := slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, "*"+title+"*", false, false), nil, nil)
+	counts := slack.NewSectionBlock(nil, []*slack.TextBlockObject{
+		slack.NewTextBlockObject(slack.MarkdownType, t("stats.approved", stats.Approved), false, false),
+		slack.NewTextBlockObject(slack.MarkdownType, t("stats.me_turnaround")+formatLatency(stats.Turnaround, stats.Timed), false, false),
+		slack.NewTextBlockObject(slack.MarkdownType, t("stats.me_assigned", stats.Assigned), false, false),
+		slack.NewTextBlockObject(slack.MarkdownType, t("stats.me_overdue", stats.Overdue), false, false),
+	}, nil)
+	if _, err := sh.API.PostEphemeral(ev.Channel, ev.User, slack.MsgOptionText(title, false), slack.MsgOptionBlocks(header, counts)); err != nil {
+		log.Printf("[ERROR] Failed to post personal stats to %s: %v", ev.User, err)
+	}
+}
+
+func (sh *SlackHandler) handleQueueStats(w http.ResponseWriter, ev *slackevents.MessageEvent) {
+	parts := strings.Fields(ev.Text)
+	personal := len(parts) > 2 && parts[2] == "me"
+	if personal {
+		parts = append(parts[:2], parts[3:]...)
+	}
+	if len(parts) > 3 {
+		sh.replyError(ev, t("stats.usage"))
+		return
+	}
+	window := defaultStatsWindow
+	if len(parts) > 2 {
+		var err error
+		if window, err = parseDuration(parts[2]); err != nil {
+			sh.replyError(ev, t("stats.usage"))
+			return
+		}
+	}
+	if personal {
+		sh.handleMyStats(ev, window)
+		return
+	}
+
+	since := time.Now().Add(-window)
+	sh.mu.Lock()
+	queues, err := sh.teamQueues()
+	sh.mu.Unlock()
+	if err != nil {
+		sh.replyQueueError(ev, err)
+		return
+	}
+
+	entries := sh.teamEntries(sh.Audit.Since(since))
+	stats := aggregateStats(entries)
+	title := t("stats.title", formatDuration(window))
+	if stats.Created+stats.Approved+stats.Removed == 0 {
+		sh.reply(ev, slack.MsgOptionText(t("stats.none", formatDuration(window)), false))
+		return
+	}
+
+	header := slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, "*"+title+"*", false, false), nil, nil)
+	counts := slack.NewSectionBlock(nil, []*slack.TextBlockObject{
+		slack.NewTextBlockObject(slack.MarkdownType, t("stats.created", stats.Created), false, false),
+		slack.NewTextBlockObject(slack.MarkdownType, t("stats.approved", stats.Approved), false, false),
+		slack.NewTextBlockObject(slack.MarkdownType, t("stats.removed", stats.Removed), false, false),
+	}, nil)
+
+	board := t("stats.no_approvals")
+	if reviewers := stats.leaderboard(); len(reviewers) > 0 {
+		var b strings.Builder
+		for i, reviewer := range reviewers {
+			if i == leaderboardSize {
+				break
+			}
+			b.WriteString(fmt.Sprintf("%d. <@%s> — %d\n", i+1, reviewer, stats.Approvals[reviewer]))
+		}
+		board = b.String()
+	}
+	leaders := slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, t("stats.top")+board, false, false), nil, nil)
+
+	latency := computeReviewLatency(entries)
+	latencies := slack.NewSectionBlock(nil, []*slack.TextBlockObject{
+		slack.NewTextBlockObject(slack.MarkdownType, t("stats.first_review")+formatLatency(latency.TimeToFirstReview, latency.Reviewed), false, false),
+		slack.NewTextBlockObject(slack.MarkdownType, t("stats.approval")+formatLatency(latency.TimeToApproval, latency.Approved), false, false),
+		slack.NewTextBlockObject(slack.MarkdownType, t("stats.in_review")+formatLatency(averageReviewTime(queues, since)), false, false),
+	}, nil)
+
+	sh.reply(ev,
+		slack.MsgOptionText(title, false),
+		slack.MsgOptionBlocks(header, counts, latencies, leaders))
+}
+
+// formatLatency renders an average latency, or "n/a" when no queue reached
+// the stage.
+func formatLatency(avg time.Duration, samples int) string {
+	if samples == 0 {
+		return "n/a"
+	}
+	return t("stats.latency", formatDuration(avg), samples)
+}
@@ -0,0 +1,26 @@
+package main
+
+import "testing"
+
+func TestAggregateStats(t *testing.T) {
+	entries := []AuditEntry{
+		{Event: EventQueueAdded, QueueID: 1, Actor: "U1"},
+		{Event: EventQueueAdded, QueueID: 2, Actor: "U1"},
+		// Queue 1 has one of the approvals it needs
+		{Event: EventQueueApproved, QueueID: 1, Actor: "<@U2>"},
+		{Event: EventQueueApproved, QueueID: 2, Actor: "<@U2>"},
+		{Event: EventQueueApproved, QueueID: 2, Actor: "<@U3>"},
+		{Event: EventQueueCompleted, QueueID: 2, Actor: "<@U3>"},
+		// Reopened and approved again
+		{Event: EventQueueApproved, QueueID: 2, Actor: "<@U3>"},
+		{Event: EventQueueCompleted, QueueID: 2, Actor: "<@U3>"},
+		{Event: EventQueueRemoved, QueueID: 1, Actor: "U1"},
+	}
+	stats := aggregateStats(entries)
+	if stats.Created != 2 || stats.Approved != 1 || stats.Removed != 1 {
+		t.Errorf("created %d, approved %d, removed %d; want 2, 1, 1", stats.Created, stats.Approved, stats.Removed)
+	}
+	if stats.Approvals["<@U2>"] != 2 || stats.Approvals["<@U3>"] != 2 {
+		t.Errorf("approvals = %v, want 2 each", stats.Approvals)
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// QueueStatus is where a queue is in its review lifecycle.
+type QueueStatus string
+
+const (
+	StatusOpen     QueueStatus = "open"
+	StatusInReview QueueStatus = "in_review"
+	StatusApproved QueueStatus = "approved"
+	StatusClosed   QueueStatus = "closed"
+)
+
+// queueTransitions lists the statuses each status may move to. Claiming a
+// queue that is already in review moves it to in_review again; whether
+// another reviewer may take it over is checked by the review command. An
+// approved queue only reopens when the approval that completed it is
+// withdrawn, so commands that reopen queues check isActive first.
+var queueTransitions = map[QueueStatus][]QueueStatus{
+	StatusOpen:     {StatusInReview, StatusApproved, StatusClosed},
+	StatusInReview: {StatusInReview, StatusOpen, StatusApproved, StatusClosed},
+	StatusApproved: {StatusOpen, StatusClosed},
+	StatusClosed:   nil,
+}
+
+func (s QueueStatus) label() string {
+	return t("status." + string(s))
+}
+
+// statusEmojis are the shortcodes that prefix each queue in the list.
+var statusEmojis = map[QueueStatus]string{
+	StatusOpen:     ":new:",
+	StatusInReview: ":eyes:",
+	StatusApproved: ":white_check_mark:",
+	StatusClosed:   ":lock:",
+}
+
+// statusEmoji returns the list emoji for the queue; an overdue queue still
+// awaiting review gets an alarm clock instead of its status.
+func (q *Queue) statusEmoji(now time.Time) string {
+	if q.isActive() && q.isOverdue(now) {
+		return ":alarm_clock:"
+	}
+	return statusEmojis[q.status()]
+}
+
+// status returns the queue's status, deriving it for queues saved before
+// Status was tracked.
+func (q *Queue) status() QueueStatus {
+	switch {
+	case q.Status != "":
+		return q.Status
+	case q.isComplete():
+		return StatusApproved
+	case q.InReviewState:
+		return StatusInReview
+	}
+	return StatusOpen
+}
+
+// isActive reports whether the queue still awaits review.
+func (q *Queue) isActive() bool {
+	status := q.status()
+	return status == StatusOpen || status == StatusInReview
+}
+
+// checkActive rejects changes to the reviewers of a queue that no longer
+// awaits review.
+func (q *Queue) checkActive() error {
+	if !q.isActive() {
+		return rejection(t("status.inactive", q.DisplayID(), q.status().label()))
+	}
+	return nil
+}
+
+// canTransition reports whether the lifecycle allows moving from one status
+// to another.
+func canTransition(from, to QueueStatus) bool {
+	return slices.Contains(queueTransitions[from], to)
+}
+
+// checkTransition rejects a move the lifecycle doesn't allow.
+func (q *Queue) checkTransition(to QueueStatus) error {
+	if from := q.status(); !canTransition(from, to) {
+		return rejection(t("status.bad_transition", q.DisplayID(), from.label(), to.label()))
+	}
+	return nil
+}
+
+// transition moves the queue to a new status. InReviewState mirrors it for
+// the store indexes, and the time spent in review is tracked across reopens.
+func (q *Queue) transition(to QueueStatus) error {
+	if err := q.checkTransition(to); err != nil {
+		return err
+	}
+	now := time.Now()
+	switch from := q.status(); {
+	case from == StatusInReview && to != StatusInReview:
+		// Queues claimed before this was tracked have no start
+		if !q.ReviewStartedAt.IsZero() {
+			q.ReviewTime += now.Sub(q.ReviewStartedAt)
+		}
+		q.ReviewStartedAt = time.Time{}
+	case from != StatusInReview && to == StatusInReview:
+		q.ReviewStartedAt = now
+	}
+	q.Status = to
+	q.InReviewState = to == StatusInReview
+	return nil
+}
+
+// timeInReview returns how long the queue has spent in review in total,
+// including the current review if it is in review at now.
+func (q *Queue) timeInReview(now time.Time) time.Duration {
+	total := q.ReviewTime
+	if q.status() == StatusInReview && !q.ReviewStartedAt.IsZero() {
+		total += now.Sub(q.ReviewStartedAt)
+	}
+	return total
+}
+
+// statusText describes the queue's status for display.
+func (q *Queue) statusText() string {
+	switch q.status() {
+	case StatusInReview:
+		if q.Reviewer != "" {
+			return t("status.text_in_review_by", q.Reviewer)
+		}
+		return t("status.text_in_review")
+	case StatusApproved:
+		return t("status.text_approved")
+	case StatusClosed:
+		return t("status.text_closed")
+	}
+	return t("status.text_open")
+}
+
+func (sh *SlackHandler) handleQueueClose(w http.ResponseWriter, ev *slackevents.MessageEvent) {
+	ref, err := sh.parseQueueID(ev.Text)
+	if err != nil {
+		sh.replyError(ev, err.Error())
+		return
+	}
+
+	sh.mu.Lock()
+	var pin reviewPin
+	queue, err := sh.findQueue(ref)
+	if err == nil {
+		queue, err = sh.Store.Update(queue.ID, func(q *Queue) error {
+			if q.Owner != ev.User && !sh.isAdmin(ev.User) {
+				return rejection(t("close.owner_only", q.Owner))
+			}
+			if err := q.transition(StatusClosed); err != nil {
+				return err
+			}
+			pin = takeReviewPin(q)
+			q.UpdatedAt = time.Now()
+			return nil
+		})
+	}
+	if err == nil {
+		sh.unpinReviewMessage(pin)
+		sh.refreshHomes(queue)
+		sh.refreshStatusCard(queue)
+	}
+	sh.mu.Unlock()
+
+	if err != nil {
+		sh.replyQueueError(ev, err)
+		return
+	}
+	msg := t("close.done", queue.DisplayID())
+	sh.API.PostMessage(ev.Channel, slack.MsgOptionText(msg, false))
+}
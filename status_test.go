@@ -0,0 +1,70 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCanTransition(t *testing.T) {
+	statuses := []QueueStatus{StatusOpen, StatusInReview, StatusApproved, StatusClosed}
+	allowed := map[[2]QueueStatus]bool{
+		{StatusOpen, StatusInReview}:     true,
+		{StatusOpen, StatusApproved}:     true,
+		{StatusOpen, StatusClosed}:       true,
+		{StatusInReview, StatusInReview}: true,
+		{StatusInReview, StatusOpen}:     true,
+		{StatusInReview, StatusApproved}: true,
+		{StatusInReview, StatusClosed}:   true,
+		{StatusApproved, StatusOpen}:     true,
+		{StatusApproved, StatusClosed}:   true,
+	}
+	for _, from := range statuses {
+		for _, to := range statuses {
+			want := allowed[[2]QueueStatus{from, to}]
+			if got := canTransition(from, to); got != want {
+				t.Errorf("canTransition(%s, %s) = %v, want %v", from, to, got, want)
+			}
+		}
+	}
+}
+
+func TestTransitionTracksReviewTime(t *testing.T) {
+	q := &Queue{Status: StatusOpen}
+	if err := q.transition(StatusInReview); err != nil {
+		t.Fatalf("open -> in_review: %v", err)
+	}
+	if !q.InReviewState || q.ReviewStartedAt.IsZero() {
+		t.Fatalf("in review: InReviewState %v, ReviewStartedAt %v", q.InReviewState, q.ReviewStartedAt)
+	}
+	q.ReviewStartedAt = q.ReviewStartedAt.Add(-time.Hour)
+	if err := q.transition(StatusApproved); err != nil {
+		t.Fatalf("in_review -> approved: %v", err)
+	}
+	if q.InReviewState || !q.ReviewStartedAt.IsZero() || q.ReviewTime < time.Hour {
+		t.Errorf("approved: InReviewState %v, ReviewStartedAt %v, ReviewTime %v", q.InReviewState, q.ReviewStartedAt, q.ReviewTime)
+	}
+	if err := q.transition(StatusInReview); err == nil {
+		t.Error("approved -> in_review was allowed")
+	}
+	if q.status() != StatusApproved {
+		t.Errorf("a rejected transition changed the status to %s", q.status())
+	}
+}
+
+func TestWithdrawApprovalReopens(t *testing.T) {
+	q := &Queue{Status: StatusApproved, Approvers: []string{"<@U2>"}, ApprovedAt: time.Now()}
+	if !withdrawApproval(q, "U2") {
+		t.Fatal("withdrawApproval found no approval")
+	}
+	if q.status() != StatusOpen || !q.ApprovedAt.IsZero() {
+		t.Errorf("status %s, ApprovedAt %v; want a reopened queue", q.status(), q.ApprovedAt)
+	}
+	if len(q.Approvers) != 0 || len(q.Tags) != 1 || q.Tags[0] != "<@U2>" {
+		t.Errorf("approvers %v, tags %v; want the approver pending again", q.Approvers, q.Tags)
+	}
+
+	closed := &Queue{Status: StatusClosed, Approvers: []string{"<@U2>"}}
+	if withdrawApproval(closed, "U2") || closed.status() != StatusClosed {
+		t.Errorf("withdrawing from a closed queue: status %s", closed.status())
+	}
+}
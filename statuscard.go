@@ -0,0 +1,81 @@
+package main
+
+import (
+	"log"
+
+	"github.com/slack-go/slack"
+)
+
+// postStatusCard starts a thread under the queue's "added" message with a
+// status card that later changes edit in place. It calls the Slack API, so
+// call it without holding sh.mu.
+func (sh *SlackHandler) postStatusCard(queue *Queue, channel, parentTS string) {
+	if !sh.StatusCards || parentTS == "" {
+		return
+	}
+	_, ts, err := sh.API.PostMessage(channel,
+		slack.MsgOptionText(renderStatusCard(queue), false),
+		slack.MsgOptionTS(parentTS))
+	if err != nil {
+		log.Printf("[WARN] Failed to post status card for queue %s: %v", queue.DisplayID(), err)
+		return
+	}
+
+	_, err = sh.Store.Update(queue.ID, func(q *Queue) error {
+		q.StatusChannel = channel
+		q.ThreadTS = parentTS
+		q.StatusTS = ts
+		return nil
+	})
+	if err != nil {
+		log.Printf("[WARN] Failed to record status card for queue %s: %v", queue.DisplayID(), err)
+	}
+}
+
+// refreshStatusCard edits the queue's status card in the background to show
+// its current state. It may be called with sh.mu held.
+func (sh *SlackHandler) refreshStatusCard(queue *Queue) {
+	if queue.StatusTS == "" {
+		return
+	}
+	go func(id int) {
+		// Re-read so that refreshes racing each other all show the latest state
+		current, err := sh.Store.Get(id)
+		if err != nil {
+			log.Printf("[WARN] Failed to load queue %d for its status card: %v", id, err)
+			return
+		}
+		sh.editStatusCard(current, renderStatusCard(current))
+	}(queue.ID)
+}
+
+// retireStatusCard marks the card of a removed queue. It may be called with
+// sh.mu held.
+func (sh *SlackHandler) retireStatusCard(queue *Queue) {
+	if queue.StatusTS == "" {
+		return
+	}
+	text := t("card.removed", queue.DisplayID(), queue.Title)
+	go sh.editStatusCard(queue.clone(), text)
+}
+
+func (sh *SlackHandler) editStatusCard(queue *Queue, text string) {
+	_, _, _, err := sh.API.UpdateMessage(queue.StatusChannel, queue.StatusTS, slack.MsgOptionText(text, false))
+	if err != nil {
+		log.Printf("[WARN] Failed to update status card for queue %s: %v", queue.DisplayID(), err)
+	}
+}
+
+// renderStatusCard summarizes where a queue is in its lifecycle.
+func renderStatusCard(queue *Queue) string {
+	icon := ":white_circle:"
+	switch queue.status() {
+	case StatusInReview:
+		icon = ":large_blue_circle:"
+	case StatusApproved:
+		icon = ":white_check_mark:"
+	case StatusClosed:
+		icon = ":black_circle:"
+	}
+	return t("card.status", icon, queue.statusText(), queue.approvalStatus())
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"sync"
+
+	"github.com/slack-go/slack/slackevents"
+)
+
+// ErrQueueNotFound is returned by a Store when no queue has the given ID.
+var ErrQueueNotFound = errors.New("queue not found")
+
+// Store persists queues. Implementations must be safe for concurrent use,
+// including by several bot instances sharing the same backend.
+type Store interface {
+	// Create assigns the queue a new unique ID and saves it.
+	Create(q *Queue) error
+	// Save inserts or replaces the queue under q.ID.
+	Save(q *Queue) error
+	// Get returns a copy of the queue, or ErrQueueNotFound.
+	Get(id int) (*Queue, error)
+	// Update atomically applies fn to the latest copy of the queue and saves
+	// the result. If fn returns an error nothing is saved and the error is
+	// returned. fn may run more than once, as Redis retries it after a
+	// conflicting write, and may run without its result being saved, as
+	// Postgres runs it before committing. So fn must only change q, setting
+	// anything it reports back afresh on every run; side effects such as
+	// events, pins and App Home refreshes wait until Update has succeeded.
+	Update(id int, fn func(q *Queue) error) (*Queue, error)
+	// Delete removes the queue, or returns ErrQueueNotFound.
+	Delete(id int) error
+	// List returns copies of all queues ordered by ID.
+	List() ([]*Queue, error)
+}
+
+// OpenStore returns the store selected by the config: Postgres when
+// DATABASE_URL is set, Redis when REDIS_URL is set, memory otherwise.
+func OpenStore(ctx context.Context, cfg *Config) (Store, error) {
+	switch {
+	case cfg.DatabaseURL != "":
+		log.Println("[INFO] Using Postgres queue store")
+		return NewPostgresStore(ctx, cfg.DatabaseURL)
+	case cfg.RedisURL != "":
+		log.Println("[INFO] Using Redis queue store")
+		return NewRedisStore(ctx, cfg.RedisURL)
+	default:
+		return NewMemoryStore(), nil
+	}
+}
+
+// rejection is a user-facing reason for refusing a queue change, returned
+// from Update callbacks.
+type rejection string
+
+func (r rejection) Error() string {
+	return string(r)
+}
+
+// replyQueueError reports a failed queue lookup or update to the invoking
+// user, logging unexpected store failures.
+func (sh *SlackHandler) replyQueueError(ev *slackevents.MessageEvent, err error) {
+	var reason rejection
+	switch {
+	case errors.As(err, &reason):
+		sh.replyError(ev, string(reason))
+	case errors.Is(err, ErrQueueNotFound):
+		sh.replyError(ev, t("queue.not_found"))
+	default:
+		log.Printf("[ERROR] Queue store failure: %v", err)
+		sh.replyError(ev, t("queue.store_failed"))
+	}
+}
+
+// clone returns a deep copy of the queue.
+func (q *Queue) clone() *Queue {
+	c := *q
+	c.Tags = append([]string(nil), q.Tags...)
+	c.Approvers = append([]string(nil), q.Approvers...)
+	c.Labels = append([]string(nil), q.Labels...)
+	c.Checklist = append([]ChecklistItem(nil), q.Checklist...)
+	return &c
+}
+
+// MemoryStore keeps queues in process memory. It is the default store and
+// does not survive restarts.
+type MemoryStore struct {
+	queues map[int]*Queue
+	nextID int
+	mu     sync.Mutex
+}
+
+// NewMemoryStore creates a new instance of MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		queues: make(map[int]*Queue),
+		nextID: 1,
+	}
+}
+
+func (ms *MemoryStore) Create(q *Queue) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	q.ID = ms.nextID
+	ms.nextID++
+	ms.queues[q.ID] = q.clone()
+	return nil
+}
+
+func (ms *MemoryStore) Save(q *Queue) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	ms.queues[q.ID] = q.clone()
+	if q.ID >= ms.nextID {
+		ms.nextID = q.ID + 1
+	}
+	return nil
+}
+
+func (ms *MemoryStore) Get(id int) (*Queue, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	queue, exists := ms.queues[id]
+	if !exists {
+		return nil, ErrQueueNotFound
+	}
+	return queue.clone(), nil
+}
+
+func (ms *MemoryStore) Update(id int, fn func(q *Queue) error) (*Queue, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	queue, exists := ms.queues[id]
+	if !exists {
+		return nil, ErrQueueNotFound
+	}
+
+	updated := queue.clone()
+	if err := fn(updated); err != nil {
+		return nil, err
+	}
+	ms.queues[id] = updated.clone()
+	return updated, nil
+}
+
+func (ms *MemoryStore) Delete(id int) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if _, exists := ms.queues[id]; !exists {
+		return ErrQueueNotFound
+	}
+	delete(ms.queues, id)
+	return nil
+}
+
+func (ms *MemoryStore) List() ([]*Queue, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	queues := make([]*Queue, 0, len(ms.queues))
+	for _, queue := range ms.queues {
+		queues = append(queues, queue.clone())
+	}
+	sort.Slice(queues, func(i, j int) bool { return queues[i].ID < queues[j].ID })
+	return queues, nil
+}
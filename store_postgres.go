@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// postgresTimeout bounds every store operation.
+const postgresTimeout = 5 * time.Second
+
+// PostgresStore keeps queues in Postgres so several bot instances can share
+// state.
+type PostgresStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresStore connects to databaseURL and applies the schema migrations.
+func NewPostgresStore(ctx context.Context, databaseURL string) (*PostgresStore, error) {
+	pool, err := pgxpool.New(ctx, databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("connect to postgres: %w", err)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("ping postgres: %w", err)
+	}
+
+	ps := &PostgresStore{pool: pool}
+	if err := ps.migrate(ctx); err != nil {
+		pool.Close()
+		return nil, err
+	}
+	return ps, nil
+}
+
+// migrate runs every embedded migration in name order. Migrations must be
+// idempotent.
+func (ps *PostgresStore) migrate(ctx context.Context) error {
+	names, err := fs.Glob(migrations, "migrations/*.sql")
+	if err != nil {
+		return err
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		sql, err := migrations.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		if _, err := ps.pool.Exec(ctx, string(sql)); err != nil {
+			return fmt.Errorf("apply migration %s: %w", name, err)
+		}
+		log.Printf("[INFO] Applied migration %s", name)
+	}
+	return nil
+}
+
+// Close releases the connection pool.
+func (ps *PostgresStore) Close() {
+	ps.pool.Close()
+}
+
+// Create allocates the ID from queue_id_seq and inserts the queue in one
+// transaction.
+func (ps *PostgresStore) Create(q *Queue) error {
+	ctx, cancel := context.WithTimeout(context.Background(), postgresTimeout)
+	defer cancel()
+
+	return pgx.BeginFunc(ctx, ps.pool, func(tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx, `SELECT nextval('queue_id_seq')`).Scan(&q.ID); err != nil {
+			return fmt.Errorf("allocate queue id: %w", err)
+		}
+		return upsertQueue(ctx, tx, q)
+	})
+}
+
+func (ps *PostgresStore) Save(q *Queue) error {
+	ctx, cancel := context.WithTimeout(context.Background(), postgresTimeout)
+	defer cancel()
+
+	return pgx.BeginFunc(ctx, ps.pool, func(tx pgx.Tx) error {
+		if err := upsertQueue(ctx, tx, q); err != nil {
+			return err
+		}
+		// Keep the sequence ahead of explicitly saved IDs, e.g. restored queues
+		_, err := tx.Exec(ctx, `SELECT setval('queue_id_seq', GREATEST($1, (SELECT last_value FROM queue_id_seq)))`, q.ID)
+		return err
+	})
+}
+
+func (ps *PostgresStore) Get(id int) (*Queue, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), postgresTimeout)
+	defer cancel()
+
+	return scanQueue(ps.pool.QueryRow(ctx, `SELECT data FROM queues WHERE id = $1`, id))
+}
+
+// Update locks the row with SELECT ... FOR UPDATE so concurrent approvals
+// from different instances are applied one after another.
+func (ps *PostgresStore) Update(id int, fn func(q *Queue) error) (*Queue, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), postgresTimeout)
+	defer cancel()
+
+	var updated *Queue
+	err := pgx.BeginFunc(ctx, ps.pool, func(tx pgx.Tx) error {
+		queue, err := scanQueue(tx.QueryRow(ctx, `SELECT data FROM queues WHERE id = $1 FOR UPDATE`, id))
+		if err != nil {
+			return err
+		}
+		if err := fn(queue); err != nil {
+			return err
+		}
+		queue.ID = id
+		updated = queue
+		return upsertQueue(ctx, tx, queue)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (ps *PostgresStore) Delete(id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), postgresTimeout)
+	defer cancel()
+
+	tag, err := ps.pool.Exec(ctx, `DELETE FROM queues WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrQueueNotFound
+	}
+	return nil
+}
+
+func (ps *PostgresStore) List() ([]*Queue, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), postgresTimeout)
+	defer cancel()
+
+	rows, err := ps.pool.Query(ctx, `SELECT data FROM queues ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var queues []*Queue
+	for rows.Next() {
+		queue, err := scanQueue(rows)
+		if err != nil {
+			return nil, err
+		}
+		queues = append(queues, queue)
+	}
+	return queues, rows.Err()
+}
+
+func upsertQueue(ctx context.Context, tx pgx.Tx, q *Queue) error {
+	data, err := json.Marshal(q)
+	if err != nil {
+		return err
+	}
+
+	tags := q.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	var archivedAt *time.Time
+	if q.isArchived() {
+		archivedAt = &q.ArchivedAt
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO queues (id, channel, owner, in_review, tags, created_at, archived_at, data)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (id) DO UPDATE SET
+			channel = EXCLUDED.channel,
+			owner = EXCLUDED.owner,
+			in_review = EXCLUDED.in_review,
+			tags = EXCLUDED.tags,
+			archived_at = EXCLUDED.archived_at,
+			data = EXCLUDED.data`,
+		q.ID, q.Channel, q.Owner, q.InReviewState, tags, q.CreatedAt, archivedAt, data)
+	return err
+}
+
+func scanQueue(row pgx.Row) (*Queue, error) {
+	var data []byte
+	if err := row.Scan(&data); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrQueueNotFound
+		}
+		return nil, err
+	}
+	return decodeQueue(data)
+}
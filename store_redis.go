@@ -0,0 +1,317 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis keys used by RedisStore.
+const (
+	redisNextIDKey    = "queue:next_id"
+	redisQueueIndex   = "queues"
+	redisUpdatesTopic = "queue:updates"
+)
+
+const (
+	redisTimeout       = 5 * time.Second
+	redisUpdateRetries = 10
+)
+
+func redisQueueKey(id int) string {
+	return fmt.Sprintf("queue:%d", id)
+}
+
+func redisChannelKey(channel string) string {
+	return "queues:channel:" + channel
+}
+
+// queueUpdate is published on every mutation so other instances can refresh
+// the views they have posted.
+type queueUpdate struct {
+	Instance string `json:"instance"`
+	Queue    Queue  `json:"queue"`
+	Deleted  bool   `json:"deleted,omitempty"`
+}
+
+// RedisStore keeps each queue in a hash, with a sorted set of all IDs and a
+// set of IDs per channel.
+type RedisStore struct {
+	client   *redis.Client
+	instance string
+}
+
+// NewRedisStore connects to redisURL, e.g. redis://localhost:6379/0.
+func NewRedisStore(ctx context.Context, redisURL string) (*RedisStore, error) {
+	opts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse redis url: %w", err)
+	}
+
+	client := redis.NewClient(opts)
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("ping redis: %w", err)
+	}
+	return &RedisStore{client: client, instance: randomCode()}, nil
+}
+
+// Close releases the client connections.
+func (rs *RedisStore) Close() error {
+	return rs.client.Close()
+}
+
+// Create allocates the ID with INCR, so concurrent instances never hand out
+// the same ID.
+func (rs *RedisStore) Create(q *Queue) error {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	id, err := rs.client.Incr(ctx, redisNextIDKey).Result()
+	if err != nil {
+		return fmt.Errorf("allocate queue id: %w", err)
+	}
+	q.ID = int(id)
+
+	if _, err := rs.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		return writeQueue(ctx, pipe, q, "")
+	}); err != nil {
+		return err
+	}
+	rs.publish(ctx, q, false)
+	return nil
+}
+
+func (rs *RedisStore) Save(q *Queue) error {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	err := rs.update(ctx, q.ID, func(tx *redis.Tx) error {
+		previous, err := readQueue(ctx, tx, q.ID)
+		if err != nil && !errors.Is(err, ErrQueueNotFound) {
+			return err
+		}
+		oldChannel := ""
+		if previous != nil {
+			oldChannel = previous.Channel
+		}
+
+		// Keep the counter ahead of explicitly saved IDs, e.g. restored queues
+		next, err := tx.Get(ctx, redisNextIDKey).Int()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			if q.ID > next {
+				pipe.Set(ctx, redisNextIDKey, q.ID, 0)
+			}
+			return writeQueue(ctx, pipe, q, oldChannel)
+		})
+		return err
+	}, redisQueueKey(q.ID), redisNextIDKey)
+	if err != nil {
+		return err
+	}
+	rs.publish(ctx, q, false)
+	return nil
+}
+
+func (rs *RedisStore) Get(id int) (*Queue, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	return readQueue(ctx, rs.client, id)
+}
+
+// Update watches the queue's hash so a concurrent change from another
+// instance aborts and retries the transaction.
+func (rs *RedisStore) Update(id int, fn func(q *Queue) error) (*Queue, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	var updated *Queue
+	err := rs.update(ctx, id, func(tx *redis.Tx) error {
+		queue, err := readQueue(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		oldChannel := queue.Channel
+		if err := fn(queue); err != nil {
+			return err
+		}
+		queue.ID = id
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			return writeQueue(ctx, pipe, queue, oldChannel)
+		})
+		updated = queue
+		return err
+	}, redisQueueKey(id))
+	if err != nil {
+		return nil, err
+	}
+	rs.publish(ctx, updated, false)
+	return updated, nil
+}
+
+func (rs *RedisStore) Delete(id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	var deleted *Queue
+	err := rs.update(ctx, id, func(tx *redis.Tx) error {
+		queue, err := readQueue(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, redisQueueKey(id))
+			pipe.ZRem(ctx, redisQueueIndex, id)
+			pipe.SRem(ctx, redisChannelKey(queue.Channel), id)
+			return nil
+		})
+		deleted = queue
+		return err
+	}, redisQueueKey(id))
+	if err != nil {
+		return err
+	}
+	rs.publish(ctx, deleted, true)
+	return nil
+}
+
+func (rs *RedisStore) List() ([]*Queue, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	ids, err := rs.client.ZRange(ctx, redisQueueIndex, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	cmds, err := rs.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, id := range ids {
+			pipe.HGet(ctx, "queue:"+id, "data")
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	queues := make([]*Queue, 0, len(cmds))
+	for _, cmd := range cmds {
+		data, err := cmd.(*redis.StringCmd).Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue // deleted between ZRANGE and HGET
+		}
+		if err != nil {
+			return nil, err
+		}
+		queue, err := decodeQueue(data)
+		if err != nil {
+			return nil, err
+		}
+		queues = append(queues, queue)
+	}
+	return queues, nil
+}
+
+// Subscribe calls fn for every queue changed by another instance until ctx is
+// cancelled.
+func (rs *RedisStore) Subscribe(ctx context.Context, fn func(q *Queue)) {
+	pubsub := rs.client.Subscribe(ctx, redisUpdatesTopic)
+	// The message channel isn't tied to ctx; closing the subscription ends it
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			var update queueUpdate
+			if err := json.Unmarshal([]byte(msg.Payload), &update); err != nil {
+				log.Printf("[WARN] Ignoring malformed queue update: %v", err)
+				continue
+			}
+			if update.Instance == rs.instance {
+				continue
+			}
+			fn(&update.Queue)
+		}
+	}
+}
+
+// update runs fn in a WATCH transaction on keys, retrying when another client
+// modified them first.
+func (rs *RedisStore) update(ctx context.Context, id int, fn func(tx *redis.Tx) error, keys ...string) error {
+	for attempt := 0; attempt < redisUpdateRetries; attempt++ {
+		err := rs.client.Watch(ctx, fn, keys...)
+		if !errors.Is(err, redis.TxFailedErr) {
+			return err
+		}
+	}
+	return fmt.Errorf("queue %d: too many concurrent updates", id)
+}
+
+func (rs *RedisStore) publish(ctx context.Context, q *Queue, deleted bool) {
+	payload, err := json.Marshal(queueUpdate{Instance: rs.instance, Queue: *q, Deleted: deleted})
+	if err != nil {
+		log.Printf("[ERROR] Failed to marshal queue update: %v", err)
+		return
+	}
+	if err := rs.client.Publish(ctx, redisUpdatesTopic, payload).Err(); err != nil {
+		log.Printf("[WARN] Failed to publish update for queue %d: %v", q.ID, err)
+	}
+}
+
+// writeQueue queues the commands that store q and index it, moving it out of
+// oldChannel's set if the channel changed.
+func writeQueue(ctx context.Context, pipe redis.Pipeliner, q *Queue, oldChannel string) error {
+	data, err := json.Marshal(q)
+	if err != nil {
+		return err
+	}
+
+	pipe.HSet(ctx, redisQueueKey(q.ID),
+		"channel", q.Channel,
+		"owner", q.Owner,
+		"in_review", strconv.FormatBool(q.InReviewState),
+		"archived", strconv.FormatBool(q.isArchived()),
+		"data", data)
+	pipe.ZAdd(ctx, redisQueueIndex, redis.Z{Score: float64(q.ID), Member: q.ID})
+	if oldChannel != "" && oldChannel != q.Channel {
+		pipe.SRem(ctx, redisChannelKey(oldChannel), q.ID)
+	}
+	pipe.SAdd(ctx, redisChannelKey(q.Channel), q.ID)
+	return nil
+}
+
+func readQueue(ctx context.Context, c redis.Cmdable, id int) (*Queue, error) {
+	data, err := c.HGet(ctx, redisQueueKey(id), "data").Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrQueueNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decodeQueue(data)
+}
+
+func decodeQueue(data []byte) (*Queue, error) {
+	var queue Queue
+	if err := json.Unmarshal(data, &queue); err != nil {
+		return nil, fmt.Errorf("decode queue: %w", err)
+	}
+	return &queue, nil
+}
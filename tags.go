@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// userDirectoryTTL is how long the workspace user list is cached.
+const userDirectoryTTL = 10 * time.Minute
+
+// UserDirectory resolves plain-text @names to Slack user IDs.
+type UserDirectory struct {
+	api SlackAPI
+
+	mu        sync.Mutex
+	byName    map[string]string // lowercased name -> user ID
+	loadedAt  time.Time
+	locations sync.Map // user ID -> *time.Location
+	names     sync.Map // user ID -> cachedName
+}
+
+// cachedName is a display name fetched from users.info.
+type cachedName struct {
+	name      string
+	fetchedAt time.Time
+}
+
+// inlineMentionPattern matches user mentions anywhere in a message.
+var inlineMentionPattern = regexp.MustCompile(`<@([UW][A-Z0-9]+)(\|[^>]*)?>`)
+
+// NewUserDirectory creates a new instance of UserDirectory.
+func NewUserDirectory(api SlackAPI) *UserDirectory {
+	return &UserDirectory{api: api}
+}
+
+// Lookup returns the user ID for an email address, username, display name or
+// real name, matched case-insensitively.
+func (ud *UserDirectory) Lookup(name string) (string, bool) {
+	if strings.Contains(name, "@") {
+		user, err := ud.api.GetUserByEmail(name)
+		if err != nil {
+			return "", false
+		}
+		return user.ID, true
+	}
+
+	ud.mu.Lock()
+	defer ud.mu.Unlock()
+
+	if ud.byName == nil || time.Since(ud.loadedAt) > userDirectoryTTL {
+		if err := ud.load(); err != nil {
+			log.Printf("[WARN] Failed to list users for tag lookup: %v", err)
+		}
+	}
+	id, ok := ud.byName[strings.ToLower(name)]
+	return id, ok
+}
+
+// Location returns the user's timezone from users.info, or UTC if it is
+// unknown. Results are cached.
+func (ud *UserDirectory) Location(userID string) *time.Location {
+	if loc, ok := ud.locations.Load(userID); ok {
+		return loc.(*time.Location)
+	}
+
+	loc := time.UTC
+	user, err := ud.api.GetUserInfo(userID)
+	if err != nil {
+		log.Printf("[WARN] Failed to look up timezone of %s: %v", userID, err)
+		return loc
+	}
+	if user.TZ != "" {
+		if tz, err := time.LoadLocation(user.TZ); err == nil {
+			loc = tz
+		} else {
+			loc = time.FixedZone(user.TZLabel, user.TZOffset)
+		}
+	}
+	ud.locations.Store(userID, loc)
+	return loc
+}
+
+// DisplayName returns the user's display name, falling back to the real name,
+// username or ID. Names are cached for userDirectoryTTL.
+func (ud *UserDirectory) DisplayName(userID string) string {
+	if cached, ok := ud.names.Load(userID); ok && time.Since(cached.(cachedName).fetchedAt) < userDirectoryTTL {
+		return cached.(cachedName).name
+	}
+
+	user, err := ud.api.GetUserInfo(userID)
+	if err != nil {
+		log.Printf("[WARN] Failed to look up name of %s: %v", userID, err)
+		return userID
+	}
+	name := userID
+	for _, candidate := range []string{user.Profile.DisplayName, user.RealName, user.Name} {
+		if candidate != "" {
+			name = candidate
+			break
+		}
+	}
+	ud.names.Store(userID, cachedName{name: name, fetchedAt: time.Now()})
+	return name
+}
+
+// Unmention replaces user mentions in text with plain @names, so read-only
+// views don't ping anyone. Keep real mentions where a ping is intended.
+func (ud *UserDirectory) Unmention(text string) string {
+	return inlineMentionPattern.ReplaceAllStringFunc(text, func(mention string) string {
+		return "@" + ud.DisplayName(inlineMentionPattern.FindStringSubmatch(mention)[1])
+	})
+}
+
+// load refreshes the name index from users.list. Callers must hold ud.mu.
+func (ud *UserDirectory) load() error {
+	users, err := ud.api.GetUsers()
+	if err != nil {
+		return err
+	}
+
+	byName := make(map[string]string, len(users)*3)
+	for _, user := range users {
+		if user.Deleted || user.IsBot {
+			continue
+		}
+		for _, name := range []string{user.Profile.DisplayName, user.RealName, user.Name} {
+			if name != "" {
+				byName[strings.ToLower(name)] = user.ID
+			}
+		}
+	}
+	ud.byName = byName
+	ud.loadedAt = time.Now()
+	return nil
+}
+
+// splitTagsAndLabels separates reviewer mentions (tokens starting with @ or a
+// resolved <@U123> mention) from category labels such as "hotfix". Other
+// Slack tokens, such as channel links or <!here>, are dropped: they are not
+// labels and echoing them could ping people.
+func splitTagsAndLabels(tokens []string) (tags, labels []string) {
+	seen := make(map[string]bool)
+	for _, token := range tokens {
+		if strings.HasPrefix(token, "@") || strings.HasPrefix(token, "<@") {
+			tags = append(tags, token)
+			continue
+		}
+		if strings.ContainsAny(token, "<>") {
+			continue
+		}
+		label := normalizeLabel(token)
+		if label != "" && !seen[label] {
+			seen[label] = true
+			labels = append(labels, label)
+		}
+	}
+	return tags, labels
+}
+
+// normalizeLabel lowercases a label and drops a leading '#'.
+func normalizeLabel(label string) string {
+	return strings.ToLower(strings.TrimPrefix(label, "#"))
+}
+
+// withLabel keeps the queues carrying the label.
+func withLabel(queues []*Queue, label string) []*Queue {
+	var labelled []*Queue
+	for _, queue := range queues {
+		for _, l := range queue.Labels {
+			if l == label {
+				labelled = append(labelled, queue)
+				break
+			}
+		}
+	}
+	return labelled
+}
+
+// normalizeTags rewrites tags into the canonical <@U123> form so they match
+// the approver's mention, dropping duplicates. Tokens starting with @ that
+// don't resolve to a user are returned as unresolved. It calls the Slack API,
+// so call it before taking sh.mu.
+func (sh *SlackHandler) normalizeTags(tags []string) (normalized, unresolved []string) {
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		canonical := tag
+		switch {
+		case mentionPattern.MatchString(tag):
+			id, _ := parseMention(tag)
+			canonical = fmt.Sprintf("<@%s>", id)
+		case strings.HasPrefix(tag, "@"):
+			id, ok := sh.Users.Lookup(strings.TrimPrefix(tag, "@"))
+			if !ok {
+				unresolved = append(unresolved, tag)
+				continue
+			}
+			canonical = fmt.Sprintf("<@%s>", id)
+		}
+
+		if !seen[canonical] {
+			seen[canonical] = true
+			normalized = append(normalized, canonical)
+		}
+	}
+	return normalized, unresolved
+}
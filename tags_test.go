@@ -0,0 +1,18 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitTagsAndLabels(t *testing.T) {
+	tokens := []string{"<@U1>", "@bob", "Hotfix", "#hotfix", "<!here>", "<!subteam^S123|@backend>",
+		"<#C123|general>", "<https://example.com>", "x<!channel>", "backend"}
+	tags, labels := splitTagsAndLabels(tokens)
+	if want := []string{"<@U1>", "@bob"}; !slices.Equal(tags, want) {
+		t.Errorf("tags = %q, want %q", tags, want)
+	}
+	if want := []string{"hotfix", "backend"}; !slices.Equal(labels, want) {
+		t.Errorf("labels = %q, want %q", labels, want)
+	}
+}
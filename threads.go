@@ -0,0 +1,65 @@
+package main
+
+import (
+	"log"
+	"strings"
+
+	"github.com/slack-go/slack/slackevents"
+)
+
+// inThread reports whether the queue was added in the thread, or announced by
+// the message that starts it.
+func (q *Queue) inThread(threadTS string) bool {
+	return threadTS != "" && (q.SourceThreadTS == threadTS || q.ThreadTS == threadTS)
+}
+
+// scopeQueues keeps the queues a command run at ev refers to: those of the
+// channel or, with ThreadScoped, those of the thread it was run in. A thread
+// without queues falls back to the channel.
+func (sh *SlackHandler) scopeQueues(queues []*Queue, ev *slackevents.MessageEvent) []*Queue {
+	queues = inChannel(queues, ev.Channel)
+	if !sh.ThreadScoped || ev.ThreadTimeStamp == "" {
+		return queues
+	}
+	var scoped []*Queue
+	for _, queue := range queues {
+		if queue.inThread(ev.ThreadTimeStamp) {
+			scoped = append(scoped, queue)
+		}
+	}
+	if len(scoped) == 0 {
+		return queues
+	}
+	return scoped
+}
+
+// withThreadQueue fills in the queue ID of a command run without one in a
+// thread that has a queue, so "queue approve" in the thread approves that
+// thread's queue. With several, the newest is used.
+func (sh *SlackHandler) withThreadQueue(cmd *Command, command string, ev *slackevents.MessageEvent) string {
+	if !sh.ThreadScoped || ev.ThreadTimeStamp == "" || !strings.HasPrefix(cmd.Usage, cmd.Name+" <queueID>") {
+		return command
+	}
+	rest := strings.TrimPrefix(command, cmd.Name)
+	if fields := strings.Fields(rest); len(fields) > 0 && !strings.HasPrefix(fields[0], "--") {
+		return command
+	}
+
+	sh.mu.Lock()
+	queues, err := sh.teamQueues()
+	sh.mu.Unlock()
+	if err != nil {
+		log.Printf("[ERROR] Failed to list queues for thread %s: %v", ev.ThreadTimeStamp, err)
+		return command
+	}
+	var newest *Queue
+	for _, queue := range inChannel(queues, ev.Channel) {
+		if queue.inThread(ev.ThreadTimeStamp) && (newest == nil || queue.ID > newest.ID) {
+			newest = queue
+		}
+	}
+	if newest == nil {
+		return command
+	}
+	return cmd.Name + " " + newest.DisplayID() + rest
+}
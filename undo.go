@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// undoTTL is how long a removal can be undone.
+const undoTTL = 5 * time.Minute
+
+// undoEntry records the queues removed by a single `queue remove`.
+type undoEntry struct {
+	Queues    []Queue
+	Actor     string
+	RemovedAt time.Time
+}
+
+// pushUndo records removed queues on the channel's undo stack, dropping
+// entries that have expired. Callers must hold sh.mu.
+func (sh *SlackHandler) pushUndo(channel, actor string, removed []Queue) {
+	if len(removed) == 0 {
+		return
+	}
+
+	now := time.Now()
+	stack := sh.undo[channel][:0]
+	for _, entry := range sh.undo[channel] {
+		if now.Sub(entry.RemovedAt) < undoTTL {
+			stack = append(stack, entry)
+		}
+	}
+	sh.undo[channel] = append(stack, undoEntry{Queues: removed, Actor: actor, RemovedAt: now})
+}
+
+func (sh *SlackHandler) handleQueueUndo(w http.ResponseWriter, ev *slackevents.MessageEvent) {
+	sh.mu.Lock()
+	restored, err := sh.undoRemoval(ev.Channel, ev.User)
+	sh.mu.Unlock()
+
+	if err != nil {
+		sh.replyQueueError(ev, err)
+		return
+	}
+	msg := t("undo.restored_one", strings.Join(restored, ", "))
+	if len(restored) > 1 {
+		msg = t("undo.restored_many", strings.Join(restored, ", "))
+	}
+	sh.API.PostMessage(ev.Channel, slack.MsgOptionText(msg, false))
+}
+
+// undoRemoval restores the channel's most recent removal on behalf of actor,
+// returning a description of each restored queue. Callers must hold sh.mu.
+func (sh *SlackHandler) undoRemoval(channel, actor string) ([]string, error) {
+	stack := sh.undo[channel]
+	if len(stack) == 0 || time.Since(stack[len(stack)-1].RemovedAt) >= undoTTL {
+		delete(sh.undo, channel)
+		return nil, rejection(t("undo.nothing"))
+	}
+
+	entry := stack[len(stack)-1]
+	if entry.Actor != actor && !sh.isAdmin(actor) {
+		return nil, rejection(t("undo.actor_only", entry.Actor))
+	}
+	taken, err := sh.takenKeys()
+	if err != nil {
+		return nil, err
+	}
+	sh.undo[channel] = stack[:len(stack)-1]
+
+	var restored []string
+	for _, snapshot := range entry.Queues {
+		queue := snapshot
+		if queue.Key != "" {
+			if taken[strings.ToLower(queue.Key)] {
+				queue.Key = sh.IDs.nextKey(strings.SplitN(queue.Key, "-", 2)[0], taken)
+			}
+			taken[strings.ToLower(queue.Key)] = true
+		}
+
+		// Reuse the original ID unless it has been taken since
+		if _, getErr := sh.Store.Get(queue.ID); getErr == nil {
+			err = sh.Store.Create(&queue)
+		} else {
+			err = sh.Store.Save(&queue)
+		}
+		if err != nil {
+			return restored, err
+		}
+		sh.recordEvent(EventQueueRestored, &queue, actor)
+		restored = append(restored, fmt.Sprintf("%s (*%s*)", queue.DisplayID(), queue.Title))
+	}
+	return restored, nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"net/http"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/slack-go/slack/slackevents"
+)
+
+// Set at build time, e.g.
+//
+//	go build -ldflags "-X main.version=1.4.0 -X main.commit=$(git rev-parse --short HEAD)"
+var (
+	version = "dev"
+	commit  = ""
+)
+
+// BuildInfo identifies the running build.
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	GoVersion string `json:"go_version"`
+}
+
+// buildInfo returns the version and commit set through -ldflags. Without a
+// commit, the VCS revision Go stamps into binaries built from a checkout is
+// used instead.
+func buildInfo() BuildInfo {
+	info := BuildInfo{Version: version, Commit: commit, GoVersion: runtime.Version()}
+	if info.Commit == "" {
+		info.Commit = "unknown"
+		if bi, ok := debug.ReadBuildInfo(); ok {
+			for _, setting := range bi.Settings {
+				if setting.Key == "vcs.revision" {
+					info.Commit = setting.Value
+				}
+			}
+		}
+	}
+	return info
+}
+
+func (sh *SlackHandler) handleQueueVersion(w http.ResponseWriter, ev *slackevents.MessageEvent) {
+	info := buildInfo()
+	sh.replyError(ev, t("version.info", info.Version, info.Commit, info.GoVersion))
+}
+
+// HandleVersion reports the build of this instance as JSON.
+func HandleVersion(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, buildInfo())
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Queue lifecycle events sent to the outgoing webhook.
+const (
+	EventQueueAdded       = "queue.added"
+	EventQueueApproved    = "queue.approved"
+	EventQueueCompleted   = "queue.completed"
+	EventQueueReviewed    = "queue.reviewed"
+	EventQueueRemoved     = "queue.removed"
+	EventQueueRestored    = "queue.restored"
+	EventQueueMoved       = "queue.moved"
+	EventQueueReassigned  = "queue.reassigned"
+	EventQueueTransferred = "queue.transferred"
+	EventQueueArchived    = "queue.archived"
+)
+
+const (
+	webhookTimeout    = 5 * time.Second
+	webhookRetryDelay = 2 * time.Second
+	webhookAttempts   = 2
+)
+
+// WebhookEvent is the JSON payload posted to the outgoing webhook.
+type WebhookEvent struct {
+	Event     string    `json:"event"`
+	Queue     Queue     `json:"queue"`
+	Actor     string    `json:"actor"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// WebhookNotifier posts queue lifecycle events to an external URL.
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewWebhookNotifier creates a new instance of WebhookNotifier. An empty URL
+// yields a notifier whose Notify is a no-op.
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		URL:    url,
+		Client: &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+// Notify snapshots the queue and delivers the event in the background. It is
+// safe to call while holding the handler mutex.
+func (wn *WebhookNotifier) Notify(event string, queue *Queue, actor string) {
+	if wn == nil || wn.URL == "" {
+		return
+	}
+
+	payload := WebhookEvent{
+		Event:     event,
+		Queue:     *queue.clone(),
+		Actor:     actor,
+		Timestamp: time.Now().UTC(),
+	}
+	go wn.deliver(payload)
+}
+
+func (wn *WebhookNotifier) deliver(payload WebhookEvent) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("[ERROR] Failed to marshal webhook payload: %v", err)
+		return
+	}
+
+	for attempt := 1; attempt <= webhookAttempts; attempt++ {
+		err = wn.post(body)
+		if err == nil {
+			return
+		}
+		log.Printf("[WARN] Webhook delivery of %s failed (attempt %d/%d): %v", payload.Event, attempt, webhookAttempts, err)
+		if attempt < webhookAttempts {
+			time.Sleep(webhookRetryDelay)
+		}
+	}
+	log.Printf("[ERROR] Giving up on webhook delivery of %s for queue %d", payload.Event, payload.Queue.ID)
+}
+
+func (wn *WebhookNotifier) post(body []byte) error {
+	resp, err := wn.Client.Post(wn.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
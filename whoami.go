@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/slack-go/slack/slackevents"
+)
+
+// handleQueueWhoami shows the user their raw ID and the tag the bot matches
+// approvals against, with the queues in the channel that carry it, to help
+// diagnose "tag not found" errors.
+func (sh *SlackHandler) handleQueueWhoami(w http.ResponseWriter, ev *slackevents.MessageEvent) {
+	tag := fmt.Sprintf("<@%s>", ev.User)
+
+	sh.mu.Lock()
+	queues, err := sh.teamQueues()
+	sh.mu.Unlock()
+	if err != nil {
+		sh.replyQueueError(ev, err)
+		return
+	}
+
+	var tagged []string
+	for _, queue := range inChannel(queues, ev.Channel) {
+		if queue.isActive() && queue.hasTag(tag) {
+			tagged = append(tagged, queue.DisplayID())
+		}
+	}
+
+	// The tag is shown in code so Slack doesn't render it as a mention
+	msg := t("whoami.user", ev.User, tag)
+	if sh.isAdmin(ev.User) {
+		msg += t("whoami.admin")
+	}
+	if len(tagged) > 0 {
+		msg += t("whoami.tagged", strings.Join(tagged, ", "))
+	} else {
+		msg += t("whoami.untagged")
+	}
+	sh.replyError(ev, msg)
+}